@@ -4,6 +4,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/pluralsh/plural-cli/pkg/client"
+	"github.com/pluralsh/plural-cli/pkg/common"
 )
 
 func init() {
@@ -24,7 +25,7 @@ func Command(clients client.Plural) cli.Command {
 	p.service = NewService(&p.Plural)
 	return cli.Command{
 		Name:        "agents",
-		Usage:       "list and resume plural agent runs",
+		Usage:       "list, follow and resume plural agent runs",
 		Subcommands: p.commands(),
 		Category:    "AI",
 		Flags: []cli.Flag{
@@ -52,5 +53,70 @@ func (p *Plural) commands() []cli.Command {
 			ArgsUsage: "[run-id]",
 			Action:    p.handleResume,
 		},
+		{
+			Name:      "push",
+			Usage:     "upload a locally continued agent session and push its branch",
+			ArgsUsage: "{run-id}",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "repo", Usage: "path to the local clone the session was resumed in", Value: "."},
+			},
+			Action: common.RequireArgs(p.handlePush, []string{"{run-id}"}),
+		},
+		{
+			Name:        "cache",
+			Usage:       "manage locally cached agent session archives",
+			Subcommands: p.cacheCommands(),
+		},
+		{
+			Name:  "run",
+			Usage: "start a new agent run against the current repository and branch, and follow it",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "prompt", Usage: "the task for the agent", Required: true},
+				cli.StringFlag{Name: "runtime", Usage: "the agent runtime to use, one of claude, codex, gemini or opencode", Value: "claude"},
+				cli.StringFlag{Name: "repo", Usage: "path to the local clone to run against", Value: "."},
+				cli.StringFlag{Name: "mode", Usage: "whether the agent should analyze or write code", Value: "write"},
+				cli.BoolFlag{Name: "local-changes", Usage: "push unpushed commits, uncommitted changes and every untracked file that is not gitignored to a plural/agent-runs/ branch on origin and start the run from it"},
+				cli.BoolFlag{Name: "detach", Usage: "return as soon as the run is created instead of following it"},
+				cli.DurationFlag{Name: "interval", Usage: "how often to poll for new messages", Value: defaultPollInterval},
+			},
+			Action: p.handleRun,
+		},
+		{
+			Name:  "list",
+			Usage: "list recent agent runs",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "status", Usage: "only show runs with this status, eg running or failed"},
+				cli.StringFlag{Name: "runtime", Usage: "only show runs of this runtime, eg claude or codex"},
+				cli.StringFlag{Name: "repository", Usage: "only show runs against repositories matching this string"},
+				cli.IntFlag{Name: "limit", Usage: "the number of recent matching runs to show", Value: int(recentRunsLimit)},
+			},
+			Action: p.handleList,
+		},
+		{
+			Name:      "logs",
+			Usage:     "print the message history of an agent run",
+			ArgsUsage: "{run-id}",
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "follow, f", Usage: "keep streaming new messages until the run completes"},
+				cli.DurationFlag{Name: "interval", Usage: "how often to poll for new messages", Value: defaultPollInterval},
+			},
+			Action: common.RequireArgs(p.handleLogs, []string{"{run-id}"}),
+		},
+		{
+			Name:      "watch",
+			Usage:     "wait for an agent run to complete, exiting 0 on success, 1 on failure, 2 if cancelled and 3 on timeout",
+			ArgsUsage: "{run-id}",
+			Flags: []cli.Flag{
+				cli.DurationFlag{Name: "interval", Usage: "how often to poll the run status", Value: defaultPollInterval},
+				cli.DurationFlag{Name: "timeout", Usage: "give up after this long, waits forever if unset"},
+			},
+			Action: common.RequireArgs(p.handleWatch, []string{"{run-id}"}),
+		},
+		{
+			Name:      "cancel",
+			Usage:     "cancel a running agent run",
+			ArgsUsage: "{run-id}",
+			Action:    common.RequireArgs(p.handleCancel, []string{"{run-id}"}),
+		},
 	}
 }
@@ -0,0 +1,137 @@
+package agents
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/urfave/cli"
+
+	pkgagents "github.com/pluralsh/plural-cli/pkg/agents"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func (p *Plural) cacheCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:   "list",
+			Usage:  "list cached agent session archives",
+			Action: p.handleCacheList,
+		},
+		{
+			Name:  "prune",
+			Usage: "remove cached agent sessions by age or total size",
+			Flags: []cli.Flag{
+				cli.DurationFlag{Name: "max-age", Usage: "remove sessions unused for longer than this, eg 168h"},
+				cli.StringFlag{Name: "max-size", Usage: "remove the least recently used sessions until the cache fits, eg 2GB"},
+				cli.BoolFlag{Name: "all", Usage: "remove every cached session"},
+				cli.BoolFlag{Name: "dry-run", Usage: "only print the sessions that would be removed"},
+			},
+			Action: p.handleCachePrune,
+		},
+		{
+			Name:  "verify",
+			Usage: "verify cached session archives against their recorded checksums",
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "remove", Usage: "remove sessions that fail verification"},
+			},
+			Action: p.handleCacheVerify,
+		},
+	}
+}
+
+func (p *Plural) handleCacheList(_ *cli.Context) error {
+	cache, err := pkgagents.NewSessionCache()
+	if err != nil {
+		return err
+	}
+	entries, err := cache.List()
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"Run ID", "Provider", "Size", "Last Used", "State"}
+	return utils.PrintTable(entries, headers, func(entry *pkgagents.CacheEntry) ([]string, error) {
+		return []string{entry.RunID, entry.Provider, humanize.Bytes(uint64(entry.Size)), humanize.Time(entry.LastUsed), cacheState(entry)}, nil
+	})
+}
+
+func (p *Plural) handleCachePrune(c *cli.Context) error {
+	opts := pkgagents.PruneOptions{
+		MaxAge: c.Duration("max-age"),
+		All:    c.Bool("all"),
+		DryRun: c.Bool("dry-run"),
+	}
+	if maxSize := c.String("max-size"); maxSize != "" {
+		size, err := humanize.ParseBytes(maxSize)
+		if err != nil {
+			return fmt.Errorf("invalid --max-size %q: %w", maxSize, err)
+		}
+		opts.MaxSize = int64(size)
+	}
+	if opts.MaxAge <= 0 && opts.MaxSize <= 0 && !opts.All {
+		return fmt.Errorf("one of --max-age, --max-size or --all is required")
+	}
+
+	cache, err := pkgagents.NewSessionCache()
+	if err != nil {
+		return err
+	}
+	pruned, err := cache.Prune(opts)
+	if err != nil {
+		return err
+	}
+
+	var freed int64
+	for _, entry := range pruned {
+		freed += entry.Size
+	}
+	if opts.DryRun {
+		for _, entry := range pruned {
+			fmt.Printf("would remove %s/%s (%s, last used %s)\n", entry.Provider, entry.RunID, humanize.Bytes(uint64(entry.Size)), entry.LastUsed.Format(time.RFC3339))
+		}
+		return nil
+	}
+	utils.Success("Removed %d cached sessions, freeing %s\n", len(pruned), humanize.Bytes(uint64(freed)))
+	return nil
+}
+
+func (p *Plural) handleCacheVerify(c *cli.Context) error {
+	cache, err := pkgagents.NewSessionCache()
+	if err != nil {
+		return err
+	}
+	entries, err := cache.List()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, entry := range entries {
+		if err := entry.Verify(); err != nil {
+			failed++
+			utils.Warn("%s/%s: %s\n", entry.Provider, entry.RunID, err)
+			if c.Bool("remove") {
+				if err := cache.Remove(entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if failed > 0 && !c.Bool("remove") {
+		return fmt.Errorf("%d of %d cached sessions failed verification, rerun with --remove to delete them", failed, len(entries))
+	}
+	utils.Success("Verified %d cached sessions\n", len(entries)-failed)
+	return nil
+}
+
+func cacheState(entry *pkgagents.CacheEntry) string {
+	switch {
+	case entry.Partial:
+		return "partial"
+	case entry.Metadata == nil:
+		return "unverified"
+	}
+	return "complete"
+}
@@ -0,0 +1,69 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	pkgagents "github.com/pluralsh/plural-cli/pkg/agents"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func (p *Plural) handlePush(c *cli.Context) error {
+	return p.service.Push(c)
+}
+
+func (s *Service) Push(c *cli.Context) error {
+	if err := s.client.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+	consoleClient := s.client.ConsoleClient
+
+	id := c.Args().First()
+	run, err := consoleClient.GetAgentRun(id)
+	if err != nil {
+		return err
+	}
+	if run == nil || run.GetUpload() == nil || run.GetUpload().GetSession() == nil {
+		return fmt.Errorf("agent run %s has no uploaded session, only resumed sessions can be pushed", id)
+	}
+
+	// the original archive carries the manifest and session id the local state was restored from
+	ctx := context.Background()
+	bundle, err := s.session.Download(ctx, run)
+	if err != nil {
+		return err
+	}
+
+	if s.repository == nil {
+		s.repository = pkgagents.NewGitRepository(nil, nil)
+	}
+	repoPath := c.String("repo")
+	branch, err := s.repository.Branch(repoPath)
+	if err != nil {
+		return err
+	}
+
+	utils.Highlight("Packaging %s session %s...\n", bundle.Manifest.Provider, bundle.Manifest.Session.ID)
+	archive, err := s.session.Package(ctx, bundle, repoPath, branch)
+	if err != nil {
+		return err
+	}
+
+	utils.Highlight("Pushing branch %s...\n", branch)
+	if err := s.repository.PushBranch(repoPath, branch); err != nil {
+		return err
+	}
+
+	url, err := consoleClient.AgentRunSessionUploadURL(run.ID)
+	if err != nil {
+		return err
+	}
+	if err := pkgagents.NewHTTPArchiveUploader(nil).Upload(ctx, url, archive); err != nil {
+		return err
+	}
+
+	utils.Success("Pushed session for agent run %s on branch %s\n", run.ID, branch)
+	return nil
+}
@@ -0,0 +1,76 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/urfave/cli"
+
+	pkgagents "github.com/pluralsh/plural-cli/pkg/agents"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func (p *Plural) handleRun(c *cli.Context) error {
+	return p.service.Run(c)
+}
+
+func (s *Service) Run(c *cli.Context) error {
+	prompt := strings.TrimSpace(c.String("prompt"))
+	if prompt == "" {
+		return fmt.Errorf("--prompt cannot be empty")
+	}
+	runtime, err := pkgagents.ParseRuntime(c.String("runtime"))
+	if err != nil {
+		return err
+	}
+	mode := consoleclient.AgentRunMode(strings.ToUpper(c.String("mode")))
+	if !mode.IsValid() {
+		return fmt.Errorf("unsupported agent run mode %q, must be one of analyze or write", c.String("mode"))
+	}
+
+	if s.repository == nil {
+		s.repository = pkgagents.NewGitRepository(nil, nil)
+	}
+	launch, err := s.repository.LaunchContext(c.String("repo"), c.Bool("local-changes"))
+	if err != nil {
+		return err
+	}
+	if launch.Snapshot != "" {
+		utils.Highlight("Pushed local changes to %s to start the run from\n", launch.Branch)
+	}
+
+	if err := s.client.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+	consoleClient := s.client.ConsoleClient
+
+	agentRuntime, err := consoleClient.GetAgentRuntime(runtime)
+	if err != nil {
+		return err
+	}
+
+	id, err := consoleClient.CreateAgentRun(agentRuntime.ID, consoleclient.AgentRunAttributes{
+		Prompt:     prompt,
+		Repository: launch.Repository,
+		Branch:     &launch.Branch,
+		Mode:       mode,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Success("Started %s agent run %s against %s@%s\n", strings.ToLower(string(runtime)), id, s.repoName(launch.Repository), launch.Branch)
+
+	if c.Bool("detach") {
+		return nil
+	}
+
+	if err := s.followRun(consoleClient, id, true, c.Duration("interval")); err != nil {
+		return err
+	}
+	run, err := consoleClient.GetAgentRun(id)
+	if err != nil {
+		return err
+	}
+	return s.runOutcome(run)
+}
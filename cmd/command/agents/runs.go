@@ -0,0 +1,281 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/pluralsh/console/go/polly/algorithms"
+	"github.com/samber/lo"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/common"
+	"github.com/pluralsh/plural-cli/pkg/console"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	// runsPageSize is the page size used when filtering, so sparse matches don't need a request per few runs
+	runsPageSize int64 = 100
+)
+
+// exit codes returned by `plural agents watch`, so scripts can branch on the outcome of a run
+const (
+	exitRunFailed    = 1
+	exitRunCancelled = 2
+	exitRunTimeout   = 3
+)
+
+type RunFilter struct {
+	Status     string
+	Runtime    string
+	Repository string
+}
+
+func (p *Plural) handleList(c *cli.Context) error {
+	return p.service.List(c)
+}
+
+func (p *Plural) handleLogs(c *cli.Context) error {
+	return p.service.Logs(c)
+}
+
+func (p *Plural) handleWatch(c *cli.Context) error {
+	return p.service.Watch(c)
+}
+
+func (p *Plural) handleCancel(c *cli.Context) error {
+	return p.service.Cancel(c)
+}
+
+func (s *Service) List(c *cli.Context) error {
+	if err := s.client.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	runs, err := s.listRuns(s.client.ConsoleClient, c.Int("limit"), RunFilter{
+		Status:     c.String("status"),
+		Runtime:    c.String("runtime"),
+		Repository: c.String("repository"),
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"Run ID", "Status", "Runtime", "Repo", "Branch", "Prompt"}
+	return utils.PrintTable(runs, headers, func(run *consoleclient.AgentRunMinimalFragment) ([]string, error) {
+		return []string{
+			run.ID,
+			string(run.Status),
+			s.display(s.runProvider(run)),
+			s.repoName(run.Repository),
+			s.displayRunBranch(run),
+			s.displayPrompt(run.Prompt),
+		}, nil
+	})
+}
+
+func (s *Service) Logs(c *cli.Context) error {
+	if err := s.client.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	return s.followRun(s.client.ConsoleClient, c.Args().First(), c.Bool("follow"), c.Duration("interval"))
+}
+
+func (s *Service) Watch(c *cli.Context) error {
+	if err := s.client.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	run, err := s.waitForRun(s.client.ConsoleClient, c.Args().First(), c.Duration("interval"), c.Duration("timeout"))
+	if err != nil {
+		return err
+	}
+	return s.runOutcome(run)
+}
+
+func (s *Service) Cancel(c *cli.Context) error {
+	if err := s.client.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id := c.Args().First()
+	if !common.Affirm(fmt.Sprintf("Are you sure you want to cancel agent run %s?", id), "PLURAL_AGENTS_CANCEL") {
+		return nil
+	}
+
+	if err := s.client.ConsoleClient.CancelAgentRun(id); err != nil {
+		return err
+	}
+
+	utils.Success("Cancelled agent run %s\n", id)
+	return nil
+}
+
+// followRun prints the message history of a run, and if follow is set keeps polling for new messages until
+// the run reaches a terminal state
+func (s *Service) followRun(consoleClient console.ConsoleClient, id string, follow bool, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	seen := int64(-1)
+	for {
+		// fetch the run before its messages so nothing written just before completion is missed
+		run, err := consoleClient.GetAgentRun(id)
+		if err != nil {
+			return err
+		}
+		if run == nil {
+			return fmt.Errorf("agent run %s not found", id)
+		}
+
+		messages, err := consoleClient.GetAgentRunMessages(id)
+		if err != nil {
+			return err
+		}
+		seen = s.printMessages(messages, seen)
+
+		if !follow || s.isTerminal(run.Status) {
+			if follow {
+				utils.Highlight("agent run %s finished with status %s\n", id, run.Status)
+			}
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (s *Service) printMessages(messages []*consoleclient.AgentMessageFragment, seen int64) int64 {
+	for _, message := range messages {
+		if message == nil || message.Seq <= seen {
+			continue
+		}
+		utils.Highlight("[%s] ", strings.ToLower(string(message.Role)))
+		fmt.Println(strings.TrimSpace(message.Message))
+		seen = message.Seq
+	}
+	return seen
+}
+
+func (s *Service) waitForRun(consoleClient console.ConsoleClient, id string, interval, timeout time.Duration) (*consoleclient.AgentRunMinimalFragment, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	status := consoleclient.AgentRunStatus("")
+	for {
+		run, err := consoleClient.GetAgentRun(id)
+		if err != nil {
+			return nil, err
+		}
+		if run == nil {
+			return nil, fmt.Errorf("agent run %s not found", id)
+		}
+		if run.Status != status {
+			status = run.Status
+			utils.Highlight("agent run %s is %s\n", id, strings.ToLower(string(status)))
+		}
+		if s.isTerminal(run.Status) {
+			return run, nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, cli.NewExitError(fmt.Sprintf("timed out after %s waiting for agent run %s", timeout, id), exitRunTimeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// runOutcome maps the final status of a run onto the exit code of the cli
+func (s *Service) runOutcome(run *consoleclient.AgentRunMinimalFragment) error {
+	switch run.Status {
+	case consoleclient.AgentRunStatusSuccessful:
+		utils.Success("agent run %s completed successfully\n", run.ID)
+		return nil
+	case consoleclient.AgentRunStatusCancelled:
+		return cli.NewExitError(fmt.Sprintf("agent run %s was cancelled", run.ID), exitRunCancelled)
+	}
+
+	msg := fmt.Sprintf("agent run %s failed", run.ID)
+	if run.Error != nil && *run.Error != "" {
+		msg = fmt.Sprintf("%s: %s", msg, *run.Error)
+	}
+	return cli.NewExitError(msg, exitRunFailed)
+}
+
+func (s *Service) isTerminal(status consoleclient.AgentRunStatus) bool {
+	switch status {
+	case consoleclient.AgentRunStatusSuccessful, consoleclient.AgentRunStatusFailed, consoleclient.AgentRunStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// listRuns pages through the most recent runs until limit of them match the filter, the api can't filter runs
+// itself
+func (s *Service) listRuns(consoleClient console.ConsoleClient, limit int, filter RunFilter) ([]*consoleclient.AgentRunMinimalFragment, error) {
+	pageSize := int64(limit)
+	if filter != (RunFilter{}) {
+		pageSize = max(pageSize, runsPageSize)
+	}
+
+	pager := algorithms.NewPager[*consoleclient.ListAgentRunsMinimal_AgentRuns_Edges](pageSize, func(page *string, size int64) ([]*consoleclient.ListAgentRunsMinimal_AgentRuns_Edges, *algorithms.PageInfo, error) {
+		resp, err := consoleClient.ListAgentRunsPage(page, size)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			return nil, &algorithms.PageInfo{PageSize: size}, nil
+		}
+		return resp.Edges, &algorithms.PageInfo{
+			HasNext:  resp.PageInfo.HasNextPage,
+			After:    resp.PageInfo.EndCursor,
+			PageSize: size,
+		}, nil
+	})
+
+	runs := make([]*consoleclient.AgentRunMinimalFragment, 0, limit)
+	for pager.HasNext() && len(runs) < limit {
+		edges, err := pager.NextPage()
+		if err != nil {
+			return nil, err
+		}
+		nodes := lo.Map(edges, func(edge *consoleclient.ListAgentRunsMinimal_AgentRuns_Edges, _ int) *consoleclient.AgentRunMinimalFragment {
+			return edge.GetNode()
+		})
+		runs = append(runs, s.filterRuns(nodes, filter)...)
+	}
+
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+func (s *Service) filterRuns(runs []*consoleclient.AgentRunMinimalFragment, filter RunFilter) []*consoleclient.AgentRunMinimalFragment {
+	filtered := make([]*consoleclient.AgentRunMinimalFragment, 0, len(runs))
+	for _, run := range runs {
+		if run == nil {
+			continue
+		}
+		if filter.Status != "" && !strings.EqualFold(string(run.Status), filter.Status) {
+			continue
+		}
+		if filter.Runtime != "" && !strings.EqualFold(s.runProvider(run), filter.Runtime) {
+			continue
+		}
+		if filter.Repository != "" && !strings.Contains(strings.ToLower(run.Repository), strings.ToLower(filter.Repository)) {
+			continue
+		}
+		filtered = append(filtered, run)
+	}
+	return filtered
+}
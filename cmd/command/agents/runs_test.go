@@ -0,0 +1,132 @@
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/test/mocks"
+)
+
+func TestFilterRuns(t *testing.T) {
+	service := &Service{}
+	runs := []*consoleclient.AgentRunMinimalFragment{
+		{
+			ID:         "run-1",
+			Status:     consoleclient.AgentRunStatusRunning,
+			Repository: "git@github.com:pluralsh/plural.git",
+			Runtime:    &consoleclient.AgentRunMinimalFragment_Runtime{Type: consoleclient.AgentRuntimeTypeClaude},
+		},
+		{
+			ID:         "run-2",
+			Status:     consoleclient.AgentRunStatusFailed,
+			Repository: "git@github.com:pluralsh/console.git",
+			Runtime:    &consoleclient.AgentRunMinimalFragment_Runtime{Type: consoleclient.AgentRuntimeTypeCodex},
+		},
+		nil,
+	}
+
+	tests := []struct {
+		name     string
+		filter   RunFilter
+		expected []string
+	}{
+		{name: "no filter skips nil runs", filter: RunFilter{}, expected: []string{"run-1", "run-2"}},
+		{name: "status is case insensitive", filter: RunFilter{Status: "failed"}, expected: []string{"run-2"}},
+		{name: "runtime", filter: RunFilter{Runtime: "claude"}, expected: []string{"run-1"}},
+		{name: "repository substring", filter: RunFilter{Repository: "Console"}, expected: []string{"run-2"}},
+		{name: "combined filters", filter: RunFilter{Status: "running", Runtime: "codex"}, expected: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filtered := service.filterRuns(runs, test.filter)
+			ids := make([]string, 0, len(filtered))
+			for _, run := range filtered {
+				ids = append(ids, run.ID)
+			}
+			if len(ids) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != test.expected[i] {
+					t.Fatalf("expected %v, got %v", test.expected, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestListRunsPagesUntilLimit(t *testing.T) {
+	page := func(cursor *string, hasNext bool, statuses ...consoleclient.AgentRunStatus) *consoleclient.ListAgentRunsMinimal_AgentRuns {
+		edges := make([]*consoleclient.ListAgentRunsMinimal_AgentRuns_Edges, 0, len(statuses))
+		for i, status := range statuses {
+			edges = append(edges, &consoleclient.ListAgentRunsMinimal_AgentRuns_Edges{
+				Node: &consoleclient.AgentRunMinimalFragment{ID: fmt.Sprintf("%s-%d", lo.FromPtr(cursor), i), Status: status},
+			})
+		}
+		return &consoleclient.ListAgentRunsMinimal_AgentRuns{
+			Edges:    edges,
+			PageInfo: consoleclient.PageInfoFragment{HasNextPage: hasNext, EndCursor: cursor},
+		}
+	}
+
+	client := mocks.NewConsoleClient(t)
+	client.On("ListAgentRunsPage", (*string)(nil), runsPageSize).
+		Return(page(lo.ToPtr("a"), true, consoleclient.AgentRunStatusRunning, consoleclient.AgentRunStatusFailed), nil).Once()
+	client.On("ListAgentRunsPage", mock.MatchedBy(func(after *string) bool { return lo.FromPtr(after) == "a" }), runsPageSize).
+		Return(page(lo.ToPtr("b"), true, consoleclient.AgentRunStatusFailed, consoleclient.AgentRunStatusFailed), nil).Once()
+
+	runs, err := (&Service{}).listRuns(client, 2, RunFilter{Status: "failed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := lo.Map(runs, func(run *consoleclient.AgentRunMinimalFragment, _ int) string { return run.ID })
+	if fmt.Sprint(ids) != "[a-1 b-0]" {
+		t.Fatalf("expected the first two failed runs across pages, got %v", ids)
+	}
+}
+
+func TestRunOutcomeExitCodes(t *testing.T) {
+	service := &Service{}
+	reason := "agent crashed"
+
+	tests := []struct {
+		status consoleclient.AgentRunStatus
+		code   int
+	}{
+		{status: consoleclient.AgentRunStatusSuccessful, code: 0},
+		{status: consoleclient.AgentRunStatusFailed, code: exitRunFailed},
+		{status: consoleclient.AgentRunStatusCancelled, code: exitRunCancelled},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			if !service.isTerminal(test.status) {
+				t.Fatalf("expected %s to be terminal", test.status)
+			}
+
+			err := service.runOutcome(&consoleclient.AgentRunMinimalFragment{ID: "run-1", Status: test.status, Error: &reason})
+			if test.code == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var exitErr cli.ExitCoder
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != test.code {
+				t.Fatalf("expected exit code %d, got %v", test.code, err)
+			}
+		})
+	}
+
+	if service.isTerminal(consoleclient.AgentRunStatusRunning) {
+		t.Fatalf("expected running to not be terminal")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/samber/lo"
 	"github.com/urfave/cli"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
 )
 
 func (p *Plural) cdServices() cli.Command {
@@ -99,10 +100,46 @@ func (p *Plural) cdServiceCommands() []cli.Command {
 			Flags:     []cli.Flag{cli.StringFlag{Name: "o", Usage: "output format"}},
 			Usage:     "describe cluster service",
 		},
+		{
+			Name:      "snapshot",
+			ArgsUsage: "@{cluster-handle}/{serviceName}",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleSnapshotService, []string{"@{cluster-handle}/{serviceName}"})),
+			Usage:     "prints the bindings of a service as yaml, for use with --bindings-file when rendering offline",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "reveal-secrets",
+					Usage: "include service configuration, context values and secret stack outputs instead of redacting them",
+				},
+			},
+		},
+		{
+			Name:      "helm-template",
+			ArgsUsage: "@{cluster-handle}/{serviceName}",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleHelmTemplate, []string{"@{cluster-handle}/{serviceName}"})),
+			Usage:     "renders the helm chart of a service with its merged values and explains where each value came from",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "dir",
+					Usage: "a local copy of the service's git folder, otherwise its repository is cloned when the chart or values files live in git",
+				},
+				cli.StringFlag{
+					Name:  "repo-url",
+					Usage: "the url of the chart repository, for charts from a helm repository defined in the cluster",
+				},
+				cli.BoolFlag{
+					Name:  "values-only",
+					Usage: "only print where each value came from",
+				},
+				cli.BoolFlag{
+					Name:  "manifests-only",
+					Usage: "only print the rendered manifests",
+				},
+			},
+		},
 		{
 			Name:   "template",
 			Action: p.handleTemplateService,
-			Usage:  "Dry-runs templating a .liquid or .tpl file with either a full service as params or custom config",
+			Usage:  "Dry-runs templating a .liquid or .tpl file or a whole service folder with either a full service as params or custom config",
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  "service",
@@ -115,6 +152,26 @@ func (p *Plural) cdServiceCommands() []cli.Command {
 					Name:  "file",
 					Usage: "The .liquid or .tpl file you want to attempt to template.",
 				},
+				cli.StringFlag{
+					Name:  "dir",
+					Usage: "A service folder to render, including helm charts and liquid files with {% include \"path\" %} tags",
+				},
+				cli.StringSliceFlag{
+					Name:  "values",
+					Usage: "helm values files for charts in the folder, can be repeated",
+				},
+				cli.BoolFlag{
+					Name:  "templated",
+					Usage: "render plain yaml files in the folder with liquid as well",
+				},
+				cli.StringFlag{
+					Name:  "out-dir",
+					Usage: "write each rendered file into this directory instead of printing a multi-document stream",
+				},
+				cli.StringFlag{
+					Name:  "bindings-file",
+					Usage: "a snapshot written by `plural cd services snapshot` to render with, without access to the console",
+				},
 			},
 		},
 		{
@@ -142,6 +199,44 @@ func (p *Plural) cdServiceCommands() []cli.Command {
 					Name:  "dir",
 					Usage: "The directory to run the lua script from, defaults to the current working directory",
 				},
+				cli.BoolFlag{
+					Name:  "trace",
+					Usage: "log every change of values and valuesFiles with the line that made it",
+				},
+				cli.BoolFlag{
+					Name:  "repl",
+					Usage: "start an interactive lua session with the bindings and scripts loaded",
+				},
+				cli.StringFlag{
+					Name:  "bindings-file",
+					Usage: "a snapshot written by `plural cd services snapshot` to use as bindings, without access to the console",
+				},
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:      "test",
+					Action:    handleLuaTest,
+					Usage:     "runs the test functions of *_test.lua files, render() executes the service script against a fixture context",
+					ArgsUsage: "[paths...]",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "lua-file",
+							Usage: "The service .lua file tests render with render(context)",
+						},
+						cli.StringFlag{
+							Name:  "lua-dir",
+							Usage: "A directory of lua library files loaded before the service script and every test file",
+						},
+						cli.StringFlag{
+							Name:  "dir",
+							Usage: "The directory to run the service script from, defaults to the current working directory",
+						},
+						cli.StringFlag{
+							Name:  "junit",
+							Usage: "write the results to this file in the JUnit xml format",
+						},
+					},
+				},
 			},
 		},
 		{
@@ -275,17 +370,36 @@ func (p *Plural) handleCreateClusterService(c *cli.Context) error {
 }
 
 func (p *Plural) handleTemplateService(c *cli.Context) error {
-	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
-		return err
-	}
-
 	printResult := func(out []byte) error {
 		fmt.Println()
 		fmt.Println(string(out))
 		return nil
 	}
 
+	if path := c.String("bindings-file"); path != "" {
+		if c.String("service") != "" || c.String("configuration") != "" {
+			return fmt.Errorf("--bindings-file can't be combined with --service or --configuration")
+		}
+		snapshot, err := template.LoadSnapshot(path)
+		if err != nil {
+			return err
+		}
+		if dir := c.String("dir"); dir != "" {
+			return renderServiceDir(c, dir, snapshot.TemplateBindings(), snapshot.Service.Name, snapshot.Service.Namespace)
+		}
+
+		res, err := template.RenderYaml(c.String("file"), snapshot.TemplateBindings())
+		if err != nil {
+			return err
+		}
+		return printResult(res)
+	}
+
 	if identifier := c.String("service"); identifier != "" {
+		if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+			return err
+		}
+
 		serviceId, clusterName, serviceName, err := parseServiceIdentifier(identifier)
 		if err != nil {
 			return err
@@ -299,6 +413,10 @@ func (p *Plural) handleTemplateService(c *cli.Context) error {
 			return fmt.Errorf("service %s does not exist", identifier)
 		}
 
+		if dir := c.String("dir"); dir != "" {
+			return renderServiceDir(c, dir, template.ServiceBindings(existing), existing.Name, existing.Namespace)
+		}
+
 		res, err := template.RenderService(c.String("file"), existing)
 		if err != nil {
 			return err
@@ -307,8 +425,14 @@ func (p *Plural) handleTemplateService(c *cli.Context) error {
 	}
 
 	bindings := map[string]interface{}{}
-	if err := utils.YamlFile(c.String("configuration"), &bindings); err != nil {
-		return err
+	if conf := c.String("configuration"); conf != "" || c.String("dir") == "" {
+		if err := utils.YamlFile(conf, &bindings); err != nil {
+			return err
+		}
+	}
+
+	if dir := c.String("dir"); dir != "" {
+		return renderServiceDir(c, dir, bindings, "", "")
 	}
 
 	res, err := template.RenderYaml(c.String("file"), bindings)
@@ -318,6 +442,48 @@ func (p *Plural) handleTemplateService(c *cli.Context) error {
 	return printResult(res)
 }
 
+func (p *Plural) handleSnapshotService(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	service, err := getService(p.ConsoleClient, c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	snapshot := template.NewSnapshot(service, !c.Bool("reveal-secrets"))
+	out, err := sigsyaml.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+func renderServiceDir(c *cli.Context, dir string, bindings map[string]interface{}, name, namespace string) error {
+	docs, err := template.RenderDir(dir, bindings, template.DirOptions{
+		Templated:   c.Bool("templated"),
+		ValuesFiles: c.StringSlice("values"),
+		ReleaseName: name,
+		Namespace:   namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if out := c.String("out-dir"); out != "" {
+		if err := template.WriteDir(out, docs); err != nil {
+			return err
+		}
+		utils.Success("rendered %d files into %s\n", len(docs), out)
+		return nil
+	}
+
+	fmt.Print(string(template.Combine(docs)))
+	return nil
+}
+
 func (p *Plural) handleCloneClusterService(c *cli.Context) error {
 	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
 		return err
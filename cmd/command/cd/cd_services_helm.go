@@ -0,0 +1,189 @@
+package cd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pluralsh/console/go/client"
+	"github.com/pluralsh/plural-cli/pkg/cd/luascript"
+	"github.com/pluralsh/plural-cli/pkg/cd/template"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+	"github.com/pluralsh/plural-cli/pkg/utils/git"
+	"github.com/samber/lo"
+	"github.com/urfave/cli"
+)
+
+func (p *Plural) handleHelmTemplate(c *cli.Context) error {
+	if c.Bool("values-only") && c.Bool("manifests-only") {
+		return fmt.Errorf("cannot specify both --values-only and --manifests-only flags")
+	}
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	svc, err := getService(p.ConsoleClient, c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	helm := svc.Helm
+	if helm == nil {
+		helm = &client.HelmSpecFragment{}
+	}
+
+	dir := c.String("dir")
+	if needsServiceFolder(helm) {
+		folder, cleanup, err := helmServiceFolder(dir, svc)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		dir = folder
+	}
+
+	src, err := helmSource(helm, dir, c.String("repo-url"))
+	if err != nil {
+		return err
+	}
+	chart, err := template.LoadChart(src)
+	if err != nil {
+		return err
+	}
+
+	layers, err := helmValuesLayers(svc, helm, dir)
+	if err != nil {
+		return err
+	}
+
+	res, err := template.RenderHelm(chart, layers, template.HelmOptions{ReleaseName: svc.Name, Namespace: svc.Namespace})
+	if err != nil {
+		return err
+	}
+
+	if !c.Bool("values-only") {
+		fmt.Print(string(template.Combine(res.Documents)))
+	}
+	if c.Bool("manifests-only") {
+		return nil
+	}
+
+	if !c.Bool("values-only") {
+		fmt.Println()
+	}
+	utils.Highlight("Values of %s, from lowest to highest precedence: %s\n\n", res.Chart, strings.Join(res.Sources, ", "))
+	return utils.PrintTable(res.Origins, []string{"Path", "Value", "Source", "Overrides"}, func(o template.ValueOrigin) ([]string, error) {
+		value, err := json.Marshal(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{o.Path, string(value), o.Source, strings.Join(o.Overrides, ", ")}, nil
+	})
+}
+
+// helmValuesLayers collects the values of a service in the order the deploy agent merges them: the valuesFiles of
+// the helm spec, then the valuesFiles and values returned by its lua script and finally the inline values.
+func helmValuesLayers(svc *client.ServiceDeploymentExtended, helm *client.HelmSpecFragment, dir string) ([]template.ValuesLayer, error) {
+	bindings := template.ServiceBindings(svc)
+	layers := make([]template.ValuesLayer, 0)
+
+	for _, file := range helm.ValuesFiles {
+		name := lo.FromPtr(file)
+		layer, err := template.ValuesFile(filepath.Join(dir, name), name, bindings)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	if helm.LuaScript != nil || helm.LuaFile != nil {
+		script, err := helmLuaScript(helm, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := luascript.Execute(script, luascript.Options{Dir: dir, Bindings: template.ServiceLuaBindings(svc)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute lua script: %w", err)
+		}
+
+		for _, name := range res.ValuesFiles {
+			layer, err := template.ValuesFile(filepath.Join(dir, name), fmt.Sprintf("%s (lua)", name), bindings)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, layer)
+		}
+		layers = append(layers, template.ValuesLayer{Source: "lua values", Values: res.Values})
+	}
+
+	if helm.Values != nil {
+		layer, err := template.ParseValues("helm.values", []byte(*helm.Values))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+func helmLuaScript(helm *client.HelmSpecFragment, dir string) (*luascript.Script, error) {
+	luaDir := ""
+	if folder := lo.FromPtr(helm.LuaFolder); folder != "" {
+		luaDir = filepath.Join(dir, folder)
+	}
+	library, err := luaLibrary(luaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if script := lo.FromPtr(helm.LuaScript); script != "" {
+		return luascript.NewScript(append(library, luascript.File{Name: "helm.luaScript", Content: script})...), nil
+	}
+	return luaScript(library, filepath.Join(dir, lo.FromPtr(helm.LuaFile)))
+}
+
+// helmSource resolves where the chart of a service comes from, services without a chart keep it in their git folder
+func helmSource(helm *client.HelmSpecFragment, dir, repoURL string) (template.HelmSource, error) {
+	chart := lo.FromPtr(helm.Chart)
+	if chart == "" && lo.FromPtr(helm.URL) == "" {
+		return template.HelmSource{Path: dir}, nil
+	}
+
+	src := template.HelmSource{
+		Chart:   chart,
+		Version: lo.FromPtr(helm.Version),
+		URL:     lo.Ternary(repoURL != "", repoURL, lo.FromPtr(helm.URL)),
+	}
+	if src.URL == "" {
+		if repo := helm.Repository; repo != nil {
+			return src, fmt.Errorf("chart %s comes from the helm repository %s/%s in the cluster, pass its url with --repo-url", chart, repo.Namespace, repo.Name)
+		}
+		return src, fmt.Errorf("chart %s has no repository, pass its url with --repo-url", chart)
+	}
+	return src, nil
+}
+
+func needsServiceFolder(helm *client.HelmSpecFragment) bool {
+	return (lo.FromPtr(helm.Chart) == "" && lo.FromPtr(helm.URL) == "") ||
+		len(helm.ValuesFiles) > 0 || helm.LuaFile != nil || helm.LuaFolder != nil
+}
+
+// helmServiceFolder returns the local copy of the git folder of a service, fetching its ref unless one is given
+func helmServiceFolder(dir string, svc *client.ServiceDeploymentExtended) (string, func(), error) {
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		return abs, func() {}, err
+	}
+
+	if svc.Repository == nil || svc.Git == nil {
+		return "", nil, fmt.Errorf("service %s has no git repository, pass a local copy of its folder with --dir", svc.Name)
+	}
+
+	utils.Warn("Fetching %s at %s\n", svc.Repository.URL, svc.Git.Ref)
+	folder, cleanup, err := git.FetchFolder(svc.Repository.URL, svc.Git.Ref, svc.Git.Folder)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch %s, pass a local copy of the service folder with --dir: %w", svc.Repository.URL, err)
+	}
+	return folder, cleanup, nil
+}
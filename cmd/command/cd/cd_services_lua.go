@@ -2,260 +2,210 @@ package cd
 
 import (
 	"fmt"
-	iofs "io/fs"
 	"os"
-	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 
-	"github.com/pluralsh/console/go/client"
+	"github.com/pluralsh/plural-cli/pkg/cd/luascript"
+	"github.com/pluralsh/plural-cli/pkg/cd/template"
 	"github.com/pluralsh/plural-cli/pkg/console"
 
 	"github.com/pluralsh/plural-cli/pkg/utils"
-	"github.com/pluralsh/polly/luautils"
 	"github.com/samber/lo"
 	"github.com/urfave/cli"
-	lua "github.com/yuin/gopher-lua"
 )
 
 func (p *Plural) handleLuaTemplate(c *cli.Context) error {
-	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
-		return err
-	}
-
 	luaFile := c.String("lua-file")
-	if luaFile == "" {
+	if luaFile == "" && !c.Bool("repl") {
 		return fmt.Errorf("expected --lua-file flag")
 	}
-	luaDir := c.String("lua-dir")
 
 	context := c.String("context")
 	serviceIdentifier := c.String("service")
+	bindingsFile := c.String("bindings-file")
 	if !lo.IsEmpty(context) && !lo.IsEmpty(serviceIdentifier) {
 		return fmt.Errorf("cannot specify both --context and --service flags")
 	}
-
-	dir := c.String("dir")
-	if dir == "" {
-		dir = "."
+	if !lo.IsEmpty(bindingsFile) && (!lo.IsEmpty(context) || !lo.IsEmpty(serviceIdentifier)) {
+		return fmt.Errorf("cannot specify --bindings-file with --context or --service flags")
 	}
 
-	luaStr, err := utils.ReadFile(luaFile)
+	dir, err := luaWorkdir(c.String("dir"))
 	if err != nil {
 		return err
 	}
 
-	if luaDir != "" {
-		luaFiles, err := luaFolder(luaDir)
-		if err != nil {
-			return err
-		}
-
-		luaStr = luaFiles + "\n\n" + luaStr
+	library, err := luaLibrary(c.String("lua-dir"))
+	if err != nil {
+		return err
 	}
-
-	dir, err = filepath.Abs(dir)
+	script, err := luaScript(library, luaFile)
 	if err != nil {
 		return err
 	}
 
-	bindings, err := luaBindings(p.ConsoleClient, context, serviceIdentifier)
+	bindings, err := p.luaTemplateBindings(bindingsFile, context, serviceIdentifier)
 	if err != nil {
 		return err
 	}
 
-	result, err := executeLuaTemplate(luaStr, dir, bindings)
+	opts := luascript.Options{Dir: dir, Bindings: bindings}
+	if c.Bool("trace") {
+		opts.Trace = func(m luascript.Mutation) {
+			utils.Warn("[trace] %s\n", m)
+		}
+	}
+
+	if c.Bool("repl") {
+		return luascript.Repl(script, opts, os.Stdin, os.Stdout)
+	}
+
+	res, err := luascript.Execute(script, opts)
 	if err != nil {
 		return err
 	}
 
 	utils.Highlight("Final lua output:\n\n")
-	utils.NewYAMLPrinter(result).PrettyPrint()
+	utils.NewYAMLPrinter(luaResult(res)).PrettyPrint()
 	return nil
 }
 
-// executeLuaTemplate runs the given Lua script with the provided bindings and working directory,
-// and returns a map with "values" and "valuesFiles" keys.
-func executeLuaTemplate(luaStr, dir string, bindings map[string]interface{}) (map[string]interface{}, error) {
-	values := map[interface{}]interface{}{}
-	valuesFiles := []string{}
-
-	L := luautils.NewLuaState(dir)
-	defer L.Close()
-
-	// Register global values and valuesFiles in Lua
-	valuesTable := L.NewTable()
-	L.SetGlobal("values", valuesTable)
-
-	valuesFilesTable := L.NewTable()
-	L.SetGlobal("valuesFiles", valuesFilesTable)
-
-	for name, binding := range bindings {
-		L.SetGlobal(name, luautils.GoValueToLuaValue(L, binding))
+func handleLuaTest(c *cli.Context) error {
+	dir, err := luaWorkdir(c.String("dir"))
+	if err != nil {
+		return err
 	}
 
-	if err := L.DoString(luaStr); err != nil {
-		return nil, err
+	library, err := luaLibrary(c.String("lua-dir"))
+	if err != nil {
+		return err
 	}
 
-	if err := luautils.MapLua(L.GetGlobal("values").(*lua.LTable), &values); err != nil {
-		return nil, err
+	var script *luascript.Script
+	if luaFile := c.String("lua-file"); luaFile != "" {
+		if script, err = luaScript(library, luaFile); err != nil {
+			return err
+		}
 	}
 
-	if err := luautils.MapLua(L.GetGlobal("valuesFiles").(*lua.LTable), &valuesFiles); err != nil {
-		return nil, err
+	paths := c.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := luascript.FindTests(paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no *_test.lua files found in %s", strings.Join(paths, ", "))
 	}
 
-	return map[string]interface{}{
-		"values":      luautils.SanitizeValue(values),
-		"valuesFiles": valuesFiles,
-	}, nil
-}
+	suites := luascript.RunTests(files, luascript.TestOptions{Script: script, Library: library, Dir: dir})
 
-func luaFolder(folder string) (string, error) {
-	luaFiles := make([]string, 0)
-	if err := filepath.WalkDir(folder, func(path string, info iofs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	failed := 0
+	for _, suite := range suites {
+		if suite.Error != "" {
+			failed++
+			utils.Error("ERROR %s\n%s\n", suite.File, suite.Error)
+			continue
 		}
-		if info.IsDir() {
-			return nil
-		}
-
-		if strings.HasSuffix(info.Name(), ".lua") {
-			luaPath, err := filepath.Rel(folder, path)
-			if err != nil {
-				return err
+		for _, tc := range suite.Cases {
+			if tc.Failure != "" {
+				failed++
+				utils.Error("FAIL %s %s (%s)\n%s\n", suite.File, tc.Name, tc.Duration, tc.Failure)
+				continue
 			}
-			luaFiles = append(luaFiles, luaPath)
+			utils.Success("PASS %s %s (%s)\n", suite.File, tc.Name, tc.Duration)
 		}
-
-		return nil
-	}); err != nil {
-		return "", fmt.Errorf("failed to walk lua folder %s: %w", folder, err)
 	}
 
-	sort.Slice(luaFiles, func(i, j int) bool {
-		return luaFiles[i] < luaFiles[j]
-	})
-
-	luaFileContents := make([]string, 0)
-	for _, file := range luaFiles {
-		luaContents, err := os.ReadFile(path.Join(folder, file))
+	if junit := c.String("junit"); junit != "" {
+		f, err := os.Create(junit)
 		if err != nil {
-			return "", fmt.Errorf("failed to read lua file %s: %w", file, err)
+			return err
 		}
-		luaFileContents = append(luaFileContents, string(luaContents))
-	}
-
-	return strings.Join(luaFileContents, "\n\n"), nil
-}
-
-func luaBindings(client console.ConsoleClient, contextPath, serviceIdentifier string) (context map[string]interface{}, err error) {
-	if serviceIdentifier != "" {
-		service, err := getService(client, serviceIdentifier)
-		if err != nil {
-			return nil, err
+		if err := luascript.WriteJUnit(f, suites); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
 		}
-
-		return map[string]interface{}{
-			"configuration": luaConfigurationBinding(service),
-			"cluster":       luaClusterBinding(service.Cluster),
-			"contexts":      luaContextsBinding(service),
-			"imports":       luaImportsBinding(service),
-			"service":       luaServiceBinding(service),
-		}, nil
 	}
 
-	if contextPath != "" {
-		err = utils.YamlFile(contextPath, &context)
-		return
+	if failed > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d lua tests failed", failed), 1)
 	}
-
-	return
+	return nil
 }
 
-func luaConfigurationBinding(svc *client.ServiceDeploymentExtended) map[string]string {
-	res := map[string]string{}
-	for _, config := range svc.Configuration {
-		res[config.Name] = config.Value
+func luaWorkdir(dir string) (string, error) {
+	if dir == "" {
+		dir = "."
 	}
-	return res
+	return filepath.Abs(dir)
 }
 
-func luaClusterBinding(cluster *client.BaseClusterFragment) map[string]interface{} {
-	res := map[string]interface{}{
-		"ID":             cluster.ID,
-		"Self":           cluster.Self,
-		"Handle":         cluster.Handle,
-		"Name":           cluster.Name,
-		"Version":        cluster.Version,
-		"CurrentVersion": cluster.CurrentVersion,
-		"KasUrl":         cluster.KasURL,
-		"Tags":           luaClusterTagsBinding(cluster.Tags),
-		"Metadata":       cluster.Metadata,
-		"Distro":         cluster.Distro,
-		// "ConsoleDNS":     args.ConsoleDNS(),
-	}
-	for k, v := range res {
-		res[strings.ToLower(k)] = v
+// luaLibrary reads the library files of luaDir, which the deploy agent prepends to the lua script of a service
+func luaLibrary(luaDir string) ([]luascript.File, error) {
+	if luaDir == "" {
+		return nil, nil
 	}
-	res["kasUrl"] = cluster.KasURL
-	res["currentVersion"] = cluster.CurrentVersion
-	return res
+	return luascript.Folder(luaDir)
 }
 
-func luaClusterTagsBinding(tags []*client.ClusterTags) map[string]string {
-	res := map[string]string{}
-	for _, tag := range tags {
-		res[tag.Name] = tag.Value
+// luaScript prepends the library files to the script, like the deploy agent does
+func luaScript(library []luascript.File, luaFile string) (*luascript.Script, error) {
+	files := append(make([]luascript.File, 0, len(library)+1), library...)
+	if luaFile != "" {
+		content, err := utils.ReadFile(luaFile)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, luascript.File{Name: luaFile, Content: content})
 	}
-	return res
+	return luascript.NewScript(files...), nil
 }
 
-func luaContextsBinding(svc *client.ServiceDeploymentExtended) map[string]map[string]interface{} {
-	res := map[string]map[string]interface{}{}
-	for _, context := range svc.Contexts {
-		res[context.Name] = context.Configuration
+func luaResult(res *luascript.Result) map[string]interface{} {
+	return map[string]interface{}{
+		"values":      res.Values,
+		"valuesFiles": res.ValuesFiles,
 	}
-	return res
 }
 
-func luaImportsBinding(svc *client.ServiceDeploymentExtended) map[string]map[string]string {
-	res := map[string]map[string]string{}
-	for _, imp := range svc.Imports {
-		res[imp.Stack.Name] = map[string]string{}
-		for _, out := range imp.Outputs {
-			res[imp.Stack.Name][out.Name] = out.Value
+func (p *Plural) luaTemplateBindings(bindingsFile, contextPath, serviceIdentifier string) (map[string]interface{}, error) {
+	if bindingsFile != "" {
+		snapshot, err := template.LoadSnapshot(bindingsFile)
+		if err != nil {
+			return nil, err
 		}
+		return snapshot.LuaBindings(), nil
 	}
-	return res
+
+	if serviceIdentifier != "" {
+		if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+			return nil, err
+		}
+	}
+	return luaBindings(p.ConsoleClient, contextPath, serviceIdentifier)
 }
 
-func luaServiceBinding(svc *client.ServiceDeploymentExtended) map[string]interface{} {
-	res := map[string]interface{}{
-		"Name":      svc.Name,
-		"Namespace": svc.Namespace,
+func luaBindings(client console.ConsoleClient, contextPath, serviceIdentifier string) (context map[string]interface{}, err error) {
+	if serviceIdentifier != "" {
+		service, err := getService(client, serviceIdentifier)
+		if err != nil {
+			return nil, err
+		}
+
+		return template.ServiceLuaBindings(service), nil
 	}
-	for k, v := range res {
-		res[strings.ToLower(k)] = v
+
+	if contextPath != "" {
+		err = utils.YamlFile(contextPath, &context)
+		return
 	}
-	// if svc.Helm != nil {
-	//	helm := map[string]interface{}{
-	//		"Values":              svc.Helm.Values,
-	//		"ValuesFiles":         svc.Helm.ValuesFiles,
-	//		"LuaScript":           svc.Helm.LuaScript,
-	//		"LuaFile":             svc.Helm.LuaFile,
-	//		"LuaFolder":           svc.Helm.LuaFolder,
-	//		"KustomizePostrender": svc.Helm.KustomizePostrender,
-	//	}
-	//
-	//	for k, f := range helm {
-	//		helm[strings.ToLower(k)] = f
-	//	}
-	//	res["helm"] = helm
-	//	res["Helm"] = helm
-	//}
-	return res
+
+	return
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/pluralsh/plural-cli/pkg/common"
 	"github.com/pluralsh/plural-cli/pkg/config"
+	"github.com/pluralsh/plural-cli/pkg/console"
+	"github.com/pluralsh/plural-cli/pkg/utils"
 	"github.com/urfave/cli"
 )
 
@@ -37,6 +39,24 @@ func configCommands() []cli.Command {
 			Usage:  "imports a new config from a given token",
 			Action: common.LatestVersion(handleConfigImport),
 		},
+		{
+			Name:  "credentials",
+			Usage: "manages where your access tokens are stored",
+			Subcommands: []cli.Command{
+				{
+					Name:  "migrate",
+					Usage: "moves the tokens of your config, profiles and console config into a credential store",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:     "store",
+							Usage:    "the store to use, one of plaintext, file, file-passphrase or secret-service",
+							Required: true,
+						},
+					},
+					Action: handleMigrateCredentials,
+				},
+			},
+		},
 	}
 }
 
@@ -63,3 +83,21 @@ func handleConfigImport(c *cli.Context) error {
 
 	return config.FromToken(string(data))
 }
+
+func handleMigrateCredentials(c *cli.Context) error {
+	store, err := config.ParseCredentialStore(c.String("store"))
+	if err != nil {
+		return err
+	}
+
+	if err := config.MigrateCredentials(store); err != nil {
+		return err
+	}
+
+	if err := console.MigrateCredentials(store); err != nil {
+		return err
+	}
+
+	utils.Success("Credentials migrated to the %s store\n", c.String("store"))
+	return nil
+}
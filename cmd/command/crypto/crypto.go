@@ -1,7 +1,7 @@
 package crypto
 
 import (
-	"bytes"
+	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -23,8 +23,6 @@ import (
 	"github.com/pluralsh/plural-cli/pkg/utils"
 )
 
-var prefix = []byte("CHARTMART-ENCRYPTED")
-
 type Plural struct {
 	client.Plural
 }
@@ -50,12 +48,24 @@ func (p *Plural) cryptoCommands() []cli.Command {
 			Name:   "encrypt",
 			Usage:  "encrypts stdin and writes to stdout",
 			Action: handleEncrypt,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "path",
+					Usage: "the repo relative path of the file, bound into the ciphertext when crypto.yml sets format: v2",
+				},
+			},
 		},
 		{
 			Name:      "decrypt",
 			Usage:     "decrypts stdin and writes to stdout",
 			ArgsUsage: "{file-path}",
 			Action:    handleDecrypt,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "path",
+					Usage: "the repo relative path the file must have been encrypted for",
+				},
+			},
 		},
 		{
 			Name:   "init",
@@ -113,6 +123,23 @@ func (p *Plural) cryptoCommands() []cli.Command {
 			},
 			Action: p.handleCryptoShare,
 		},
+		{
+			Name:      "unshare",
+			Usage:     "revokes the ability of a list of plural users to decrypt this repository",
+			ArgsUsage: "",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:     "email",
+					Usage:    "a email to revoke (multiple allowed)",
+					Required: true,
+				},
+				cli.BoolFlag{
+					Name:  "rotate",
+					Usage: "rotate the repo encryption key and re-encrypt all files, so revoked users cannot decrypt new commits",
+				},
+			},
+			Action: handleCryptoUnshare,
+		},
 		{
 			Name:  "setup-keys",
 			Usage: "creates an age keypair, and uploads the public key to plural for use in plural crypto share",
@@ -125,6 +152,59 @@ func (p *Plural) cryptoCommands() []cli.Command {
 			},
 			Action: p.handleSetupKeys,
 		},
+		{
+			Name:  "setup-kms",
+			Usage: "wraps the repo encryption key with an external kms, so it is never stored in plaintext locally",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:     "backend",
+					Usage:    "the kms backend to use, one of aws, gcp, azure or vault",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:     "key",
+					Usage:    "the aws key id or arn, gcp crypto key resource name, or azure/vault key name",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "region",
+					Usage: "the aws region of the key",
+				},
+				cli.StringFlag{
+					Name:  "address",
+					Usage: "the azure key vault url or vault server address",
+				},
+				cli.StringFlag{
+					Name:  "mount",
+					Usage: "the mount path of the vault transit engine",
+				},
+				cli.BoolFlag{
+					Name:  "new-key",
+					Usage: "generate a fresh repo key instead of wrapping the current one",
+				},
+			},
+			Action: handleSetupKMS,
+		},
+		{
+			Name:  "audit",
+			Usage: "scans the repo for secrets that are committed without encryption",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "history",
+					Usage: "also scan every commit in the git history",
+				},
+				cli.Float64Flag{
+					Name:  "entropy",
+					Usage: "the shannon entropy above which a string is reported as a likely secret",
+					Value: crypto.DefaultEntropyCutoff,
+				},
+				cli.BoolFlag{
+					Name:  "write",
+					Usage: "append the suggested patterns to .gitattributes",
+				},
+			},
+			Action: handleAudit,
+		},
 		{
 			Name:        "backups",
 			Usage:       "manages backups of your encryption keys",
@@ -159,37 +239,51 @@ func (p *Plural) backupCommands() []cli.Command {
 	}
 }
 
-func handleEncrypt(_ *cli.Context) error {
-	data, err := io.ReadAll(os.Stdin)
-	if bytes.HasPrefix(data, prefix) {
-		_, err := os.Stdout.Write(data)
-		if err != nil {
-			return err
-		}
-		return nil
+func handleEncrypt(c *cli.Context) error {
+	in := bufio.NewReaderSize(os.Stdin, crypto.DefaultChunkSize)
+	head, _ := in.Peek(len(crypto.Prefix))
+	if crypto.IsEncrypted(head) {
+		_, err := io.Copy(os.Stdout, in)
+		return err
 	}
 
+	cryptoProv, err := crypto.Build()
 	if err != nil {
 		return err
 	}
-	cryptoProv, err := crypto.Build()
+
+	stream, err := crypto.StreamFormat()
 	if err != nil {
 		return err
 	}
+	if !stream {
+		return encryptLegacy(cryptoProv, in)
+	}
 
-	result, err := crypto.Encrypt(cryptoProv, data)
-	if err != nil {
+	out := bufio.NewWriter(os.Stdout)
+	if err := crypto.EncryptStream(cryptoProv, c.String("path"), in, out); err != nil {
 		return err
 	}
-	_, err = os.Stdout.Write(prefix)
+	return out.Flush()
+}
+
+// encryptLegacy writes the format every cli version can read, until the repo opts into v2 in crypto.yml
+func encryptLegacy(prov crypto.Provider, in io.Reader) error {
+	data, err := io.ReadAll(in)
 	if err != nil {
 		return err
 	}
-	_, err = os.Stdout.Write(result)
+
+	result, err := crypto.Encrypt(prov, data)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	if _, err := os.Stdout.Write(crypto.Prefix); err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(result)
+	return err
 }
 
 func handleDecrypt(c *cli.Context) error {
@@ -197,44 +291,53 @@ func handleDecrypt(c *cli.Context) error {
 	if c.Args().Present() {
 		p, _ := filepath.Abs(c.Args().First())
 		f, err := os.Open(p)
-		defer func(f *os.File) {
-			_ = f.Close()
-		}(f)
 		if err != nil {
 			return err
 		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
 		file = f
 	} else {
 		file = os.Stdin
 	}
 
-	data, err := io.ReadAll(file)
-	if err != nil {
+	in := bufio.NewReaderSize(file, crypto.DefaultChunkSize)
+	head, _ := in.Peek(crypto.StreamHeadLen())
+	if !crypto.IsEncrypted(head) {
+		_, err := io.Copy(os.Stdout, in)
 		return err
 	}
-	if !bytes.HasPrefix(data, prefix) {
-		_, err := os.Stdout.Write(data)
-		if err != nil {
-			return err
-		}
-		return nil
-	}
 
 	prov, err := crypto.Build()
 	if err != nil {
 		return err
 	}
 
-	result, err := crypto.Decrypt(prov, data[len(prefix):])
+	if !crypto.IsStreamEncrypted(head) {
+		return decryptLegacy(prov, in)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	if _, err := crypto.DecryptStream(prov, c.String("path"), in, out); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+func decryptLegacy(prov crypto.Provider, in io.Reader) error {
+	data, err := io.ReadAll(in)
 	if err != nil {
 		return err
 	}
 
-	_, err = os.Stdout.Write(result)
+	result, err := crypto.Decrypt(prov, data[len(crypto.Prefix):])
 	if err != nil {
 		return err
 	}
-	return nil
+
+	_, err = os.Stdout.Write(result)
+	return err
 }
 
 func (p *Plural) handleCryptoShare(c *cli.Context) error {
@@ -252,6 +355,134 @@ func (p *Plural) handleCryptoShare(c *cli.Context) error {
 	return crypto.Flush(prov)
 }
 
+func handleCryptoUnshare(c *cli.Context) error {
+	emails := c.StringSlice("email")
+	if err := crypto.RevokeAge(emails); err != nil {
+		return err
+	}
+
+	if !c.Bool("rotate") {
+		prov, err := crypto.BuildAgeProvider()
+		if err != nil {
+			return err
+		}
+
+		utils.Warn("revoked users may still decrypt anything encrypted with the current key, rerun with --rotate to prevent this\n")
+		return crypto.Flush(prov)
+	}
+
+	utils.Highlight("===> rotating repo encryption key\n")
+	prov, err := crypto.RotateKey()
+	if err != nil {
+		return err
+	}
+
+	if err := crypto.Flush(prov); err != nil {
+		return err
+	}
+
+	utils.Highlight("===> re-encrypting all files with the new key\n")
+	if err := common.GitCommand("add", "--renormalize", ".").Run(); err != nil {
+		return err
+	}
+
+	utils.Success("Key rotated, commit and push the re-encrypted files to complete the revocation\n")
+	return nil
+}
+
+func handleSetupKMS(c *cli.Context) error {
+	conf := &crypto.KMSConfig{
+		Backend: crypto.KMSBackend(c.String("backend")),
+		Key:     c.String("key"),
+		Region:  c.String("region"),
+		Address: c.String("address"),
+		Mount:   c.String("mount"),
+	}
+
+	var key *crypto.AESKey
+	if !c.Bool("new-key") {
+		current, err := crypto.CurrentKey()
+		if err != nil {
+			return err
+		}
+		key = current
+	}
+
+	prov, err := crypto.SetupKMS(conf, key)
+	if err != nil {
+		return err
+	}
+
+	if err := crypto.Flush(prov); err != nil {
+		return err
+	}
+
+	utils.Success("Repo key is now wrapped with %s kms, commit crypto.yml and .plural-crypt/kms-key to share it\n", conf.Backend)
+	return removePlaintextKey(key)
+}
+
+// removePlaintextKey offers to delete the plaintext key a repo used before it was wrapped with a kms, other repos
+// without a kms share the same file so it is kept unless the user confirms
+func removePlaintextKey(key *crypto.AESKey) error {
+	path := crypto.KeyPath()
+	if key == nil || !utils.Exists(path) {
+		return nil
+	}
+	existing, err := crypto.Read(path)
+	if err != nil || existing.ID() != key.ID() {
+		return nil
+	}
+
+	utils.Warn("The plaintext repo key is still stored in %s\n", path)
+	if !common.Confirm(fmt.Sprintf("Delete %s? Other repos that don't use a kms can't be decrypted without it", path), "PLURAL_CRYPTO_REMOVE_PLAINTEXT_KEY") {
+		utils.Warn("Keeping %s, delete it once no other repo needs it\n", path)
+		return nil
+	}
+	return os.Remove(path)
+}
+
+func handleAudit(c *cli.Context) error {
+	report, err := crypto.Audit(crypto.AuditOptions{
+		History: c.Bool("history"),
+		Entropy: c.Float64("entropy"),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(report.Findings) > 0 {
+		headers := []string{"File", "Line", "Rule", "Match", "Commit"}
+		if err := utils.PrintTable(report.Findings, headers, func(f *crypto.AuditFinding) ([]string, error) {
+			return []string{f.Path, strconv.Itoa(f.Line), f.Rule, f.Match, f.Commit}, nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range report.Unencrypted {
+		utils.Warn("%s is covered by plural-crypt but committed unencrypted, run `git add --renormalize %s` and commit\n", file, file)
+	}
+
+	if len(report.Suggestions) > 0 {
+		if c.Bool("write") {
+			if err := crypto.AppendGitAttributes(report.Suggestions); err != nil {
+				return err
+			}
+			utils.Success("Added %d patterns to .gitattributes\n", len(report.Suggestions))
+		} else {
+			utils.Highlight("Consider adding the following to .gitattributes:\n")
+			fmt.Println(strings.Join(report.Suggestions, "\n"))
+		}
+	}
+
+	if report.Issues() > 0 {
+		return fmt.Errorf("crypto audit found %d issues", report.Issues())
+	}
+
+	utils.Success("No unencrypted secrets found\n")
+	return nil
+}
+
 func (p *Plural) handleSetupKeys(c *cli.Context) error {
 	p.InitPluralClient()
 	name := c.String("name")
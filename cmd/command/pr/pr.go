@@ -57,7 +57,7 @@ func Command(clients client.Plural) cli.Command {
 }
 
 func (p *Plural) prCommands() []cli.Command {
-	return []cli.Command{
+	commands := []cli.Command{
 		{
 			Name:   "template",
 			Usage:  "applies a pr template resource in the local source tree",
@@ -80,10 +80,10 @@ func (p *Plural) prCommands() []cli.Command {
 			Action:    common.LatestVersion(common.RequireArgs(p.handleCreatePrAutomation, []string{"{id}"})),
 			Usage:     "create PR automation",
 			ArgsUsage: "{id}",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				cli.StringFlag{Name: "context", Usage: "JSON blob string"},
 				cli.StringFlag{Name: "branch", Usage: "branch name"},
-			},
+			}, waitFlags()...),
 		},
 		{
 			Name:   "test",
@@ -100,6 +100,64 @@ func (p *Plural) prCommands() []cli.Command {
 					Usage:    "a yaml file containing the context for the PRA, will read from stdin if not present",
 					Required: false,
 				},
+				cli.StringSliceFlag{
+					Name:  "configuration",
+					Usage: "configuration key-value pairs (format: key=value), also read from PLRL_PR_* env vars. When set or when --context is passed the configuration is validated without prompting",
+				},
+				cli.StringFlag{
+					Name:  "templates",
+					Usage: "a local checkout of the creates.git folder, used instead of fetching it",
+				},
+				cli.StringFlag{
+					Name:  "repository-url",
+					Usage: "the repository to fetch creates.git from if the file has no GitRepository matching spec.repositoryRef",
+				},
+			},
+		},
+		{
+			Name:   "scaffold",
+			Action: handlePrScaffold,
+			Usage:  "generates a PR automation, its templates, a sample context and a contract test from example files",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:     "name",
+					Usage:    "the name of the PR automation",
+					Required: true,
+				},
+				cli.StringSliceFlag{
+					Name:     "from",
+					Usage:    "example files or directories, their paths become the template destinations",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "dir",
+					Usage: "where to write the PR automation, its templates and sample context, defaults to prautomations/{name}",
+				},
+				cli.StringFlag{
+					Name:  "contracts",
+					Usage: "the contracts file to add the PR automation to, created if missing",
+					Value: "contracts.yaml",
+				},
+				cli.StringSliceFlag{
+					Name:  "var",
+					Usage: "turn every occurrence of a literal into a configuration variable (format: name=literal)",
+				},
+				cli.BoolFlag{
+					Name:  "non-interactive",
+					Usage: "only use the variables passed with --var instead of prompting for them",
+				},
+			},
+		},
+		{
+			Name:   "lint",
+			Action: handleLintPrAutomation,
+			Usage:  "type checks the conditions of a PR automation CRD",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:     "file",
+					Usage:    "the file the PR automation was placed in",
+					Required: true,
+				},
 			},
 		},
 		{
@@ -116,6 +174,10 @@ func (p *Plural) prCommands() []cli.Command {
 					Name:  "validate",
 					Usage: "check if there are any local git changes and fail if so",
 				},
+				cli.BoolFlag{
+					Name:  "strict",
+					Usage: "validate each contract context against the configuration of its automation, applying defaults and failing on missing or invalid values",
+				},
 			},
 		},
 		{
@@ -123,7 +185,7 @@ func (p *Plural) prCommands() []cli.Command {
 			Action:    common.LatestVersion(common.RequireArgs(p.handleTriggerPrAutomation, []string{"{name}"})),
 			Usage:     "trigger PR automation by name with configuration",
 			ArgsUsage: "{name}",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				cli.StringSliceFlag{
 					Name:  "configuration",
 					Usage: "configuration key-value pairs (format: key=value)",
@@ -132,9 +194,14 @@ func (p *Plural) prCommands() []cli.Command {
 					Name:  "branch",
 					Usage: "branch name for the PR",
 				},
-			},
+				cli.BoolFlag{
+					Name:  "skip-validation",
+					Usage: "send the configuration without validating it against the PR automation",
+				},
+			}, waitFlags()...),
 		},
 	}
+	return append(commands, p.pullRequestCommands()...)
 }
 
 func handlePrTemplate(c *cli.Context) error {
@@ -155,18 +222,45 @@ func handlePrTemplate(c *cli.Context) error {
 }
 
 func handleTestPrAutomation(c *cli.Context) error {
-	template, err := pr.BuildCRD(c.String("file"), c.String("context"))
+	values, err := buildConfigurationValues(c)
 	if err != nil {
 		return err
 	}
 
-	if template.Spec.Creates != nil {
-		template.Spec.Creates.ExternalDir = c.String("templates")
+	template, err := pr.BuildCRDWithOptions(c.String("file"), pr.CRDOptions{
+		Context:       c.String("context"),
+		Configuration: values,
+		ExternalDir:   c.String("templates"),
+		RepositoryURL: c.String("repository-url"),
+	})
+	if err != nil {
+		return err
 	}
+	defer template.Cleanup()
 
 	return pr.Apply(template)
 }
 
+func handleLintPrAutomation(c *cli.Context) error {
+	result, err := pr.Lint(c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range result.Warnings {
+		utils.Warn("WARNING: %s\n", warning)
+	}
+	for _, lintErr := range result.Errors {
+		utils.Error("ERROR: %s\n", lintErr)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("found %d problems in %s", len(result.Errors), c.String("file"))
+	}
+
+	utils.Success("No problems found in %s\n", c.String("file"))
+	return nil
+}
+
 func handlePrContracts(c *cli.Context) error {
 	contracts, err := pr.BuildContracts(c.String("file"))
 	if err != nil {
@@ -187,15 +281,18 @@ func handlePrContracts(c *cli.Context) error {
 	}
 
 	for _, contract := range contracts.Spec.Automations {
-		template, err := pr.BuildCRD(contract.File, contract.Context)
+		template, err := pr.BuildCRDWithOptions(contract.File, pr.CRDOptions{
+			Context:     contract.Context,
+			Lenient:     !c.Bool("strict"),
+			ExternalDir: contract.ExternalDir,
+		})
 		if err != nil {
 			return err
 		}
-		if contract.ExternalDir != "" {
-			template.Spec.Creates.ExternalDir = contract.ExternalDir
-		}
 
-		if err := pr.Apply(template); err != nil {
+		err = pr.Apply(template)
+		template.Cleanup()
+		if err != nil {
 			return err
 		}
 	}
@@ -263,7 +360,7 @@ func (p *Plural) handleCreatePrAutomation(c *cli.Context) error {
 	}
 
 	utils.Success("PR %s created successfully\n", pr.ID)
-	return nil
+	return reportPullRequest(p.ConsoleClient, c, pr)
 }
 
 func (p *Plural) handleTriggerPrAutomation(c *cli.Context) error {
@@ -278,11 +375,17 @@ func (p *Plural) handleTriggerPrAutomation(c *cli.Context) error {
 	}
 
 	// Build context from CLI flags and env vars
-	context, err := buildTriggerContext(c)
+	context, err := buildConfigurationValues(c)
 	if err != nil {
 		return err
 	}
 
+	if !c.Bool("skip-validation") {
+		if context, err = pr.ValidateAutomationConfiguration(prAutomation, context); err != nil {
+			return err
+		}
+	}
+
 	contextJSON, err := json.Marshal(context)
 	if err != nil {
 		return err
@@ -301,10 +404,10 @@ func (p *Plural) handleTriggerPrAutomation(c *cli.Context) error {
 	}
 
 	utils.Success("PR %s triggered successfully\n", pr.ID)
-	return nil
+	return reportPullRequest(p.ConsoleClient, c, pr)
 }
 
-func buildTriggerContext(c *cli.Context) (map[string]interface{}, error) {
+func buildConfigurationValues(c *cli.Context) (map[string]interface{}, error) {
 	context := make(map[string]interface{})
 
 	// Parse --configuration flags (strict validation)
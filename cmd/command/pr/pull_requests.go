@@ -0,0 +1,289 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/common"
+	"github.com/pluralsh/plural-cli/pkg/console"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultListLimit    = 100
+	pullRequestPageSize = 100
+
+	// maxPollRetries bounds the consecutive failed polls while waiting, each retry doubles the delay up to
+	// maxPollBackoff
+	maxPollRetries = 5
+	maxPollBackoff = 2 * time.Minute
+)
+
+// exit codes returned when waiting on a pull request, so pipelines can branch on whether it was merged
+const (
+	exitPrFailed   = 1
+	exitPrClosed   = 2
+	exitPrTimeout  = 3
+	exitPrAPIError = 4
+)
+
+var liquidTag = regexp.MustCompile(`{{.*?}}|{%.*?%}`)
+
+type PullRequestFilter struct {
+	// Title matches the titles generated by a PR automation, pull requests don't reference the automation
+	// that created them so its title template is the only link back to it
+	Title  *regexp.Regexp
+	Status string
+}
+
+func (p *Plural) pullRequestCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:   "list",
+			Action: common.LatestVersion(p.handleListPullRequests),
+			Usage:  "list pull requests created by PR automations",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "automation", Usage: "only show pull requests whose title matches the title template of the PR automation with this name, pull requests don't reference the automation that created them"},
+				cli.StringFlag{Name: "cluster", Usage: "cluster id to filter by"},
+				cli.StringFlag{Name: "service", Usage: "service id to filter by"},
+				cli.StringFlag{Name: "status", Usage: "status to filter by, one of open, merged or closed"},
+				cli.IntFlag{Name: "limit", Usage: "maximum number of matching pull requests to show", Value: defaultListLimit},
+				cli.StringFlag{Name: "o", Usage: "output format, one of json, yaml or jsonpath=<expression>"},
+			},
+		},
+		{
+			Name:      "status",
+			Action:    common.LatestVersion(common.RequireArgs(p.handlePullRequestStatus, []string{"{id}"})),
+			Usage:     "show the status of a pull request, optionally waiting until it is merged or closed",
+			ArgsUsage: "{id}",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{Name: "o", Usage: "output format, one of json, yaml or jsonpath=<expression>"},
+			}, waitFlags()...),
+		},
+	}
+}
+
+func waitFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.BoolFlag{Name: "wait", Usage: "wait until the pull request is merged or closed, exits 0 when merged, 2 when closed, 3 on timeout and 4 if the console keeps failing"},
+		cli.DurationFlag{Name: "timeout", Usage: "how long to wait for the pull request, 0 waits forever"},
+		cli.DurationFlag{Name: "interval", Usage: "how often to poll the pull request", Value: defaultPollInterval},
+	}
+}
+
+func (p *Plural) handleListPullRequests(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	filter := PullRequestFilter{Status: c.String("status")}
+	if filter.Status != "" && !isPullRequestStatus(filter.Status) {
+		return fmt.Errorf("invalid status %q, must be one of open, merged or closed", filter.Status)
+	}
+	if name := c.String("automation"); name != "" {
+		automation, err := p.ConsoleClient.GetPrAutomationByName(name)
+		if err != nil {
+			return err
+		}
+		filter.Title = titlePattern(automation.Title)
+	}
+
+	var clusterID, serviceID *string
+	if cluster := c.String("cluster"); cluster != "" {
+		clusterID = &cluster
+	}
+	if service := c.String("service"); service != "" {
+		serviceID = &service
+	}
+
+	pullRequests, err := listPullRequests(p.ConsoleClient, clusterID, serviceID, c.Int("limit"), filter)
+	if err != nil {
+		return err
+	}
+
+	if printed, err := utils.PrintOutput(c.String("o"), pullRequests); printed || err != nil {
+		return err
+	}
+
+	headers := []string{"Id", "Status", "Title", "Url"}
+	return utils.PrintTable(pullRequests, headers, func(pr *consoleclient.PullRequestFragment) ([]string, error) {
+		return []string{pr.ID, pullRequestStatus(pr), lo.FromPtr(pr.Title), pr.URL}, nil
+	})
+}
+
+func (p *Plural) handlePullRequestStatus(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id := c.Args().Get(0)
+	if c.Bool("wait") {
+		pr, err := waitForPullRequest(p.ConsoleClient, id, c.Duration("interval"), c.Duration("timeout"))
+		if err != nil {
+			return err
+		}
+		return pullRequestOutcome(pr)
+	}
+
+	pr, err := p.ConsoleClient.GetPullRequest(id)
+	if err != nil {
+		return err
+	}
+	if printed, err := utils.PrintOutput(c.String("o"), pr); printed || err != nil {
+		return err
+	}
+
+	headers := []string{"Id", "Status", "Title", "Url"}
+	return utils.PrintTable([]*consoleclient.PullRequestFragment{pr}, headers, func(pr *consoleclient.PullRequestFragment) ([]string, error) {
+		return []string{pr.ID, pullRequestStatus(pr), lo.FromPtr(pr.Title), pr.URL}, nil
+	})
+}
+
+// reportPullRequest reports a pull request created by `pr create` or `pr trigger` and waits on it if asked to
+func reportPullRequest(consoleClient console.ConsoleClient, c *cli.Context, pr *consoleclient.PullRequestFragment) error {
+	if pr.URL != "" {
+		fmt.Println(pr.URL)
+	}
+	if !c.Bool("wait") {
+		return nil
+	}
+
+	pr, err := waitForPullRequest(consoleClient, pr.ID, c.Duration("interval"), c.Duration("timeout"))
+	if err != nil {
+		return err
+	}
+	return pullRequestOutcome(pr)
+}
+
+func waitForPullRequest(consoleClient console.ConsoleClient, id string, interval, timeout time.Duration) (*consoleclient.PullRequestFragment, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	status := ""
+	failures := 0
+	for {
+		pr, err := consoleClient.GetPullRequest(id)
+		if err != nil {
+			failures++
+			if failures > maxPollRetries {
+				return nil, cli.NewExitError(fmt.Sprintf("failed to get pull request %s: %s", id, err), exitPrAPIError)
+			}
+			backoff := min(interval<<(failures-1), maxPollBackoff)
+			if !deadline.IsZero() && time.Now().Add(backoff).After(deadline) {
+				return nil, cli.NewExitError(fmt.Sprintf("timed out after %s waiting for pull request %s: %s", timeout, id, err), exitPrTimeout)
+			}
+			utils.Warn("failed to get pull request %s, retrying in %s: %s\n", id, backoff, err)
+			time.Sleep(backoff)
+			continue
+		}
+		failures = 0
+
+		if current := pullRequestStatus(pr); current != status {
+			status = current
+			utils.Highlight("pull request %s is %s\n", id, status)
+		}
+		if isTerminal(pr) {
+			return pr, nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, cli.NewExitError(fmt.Sprintf("timed out after %s waiting for pull request %s", timeout, id), exitPrTimeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// pullRequestOutcome maps the final status of a pull request onto the exit code of the cli
+func pullRequestOutcome(pr *consoleclient.PullRequestFragment) error {
+	switch lo.FromPtr(pr.Status) {
+	case consoleclient.PullRequestStatusMerged:
+		utils.Success("pull request %s was merged\n", pr.ID)
+		return nil
+	case consoleclient.PullRequestStatusClosed:
+		return cli.NewExitError(fmt.Sprintf("pull request %s was closed without merging", pr.ID), exitPrClosed)
+	}
+	return cli.NewExitError(fmt.Sprintf("pull request %s is still %s", pr.ID, pullRequestStatus(pr)), exitPrFailed)
+}
+
+func isTerminal(pr *consoleclient.PullRequestFragment) bool {
+	switch lo.FromPtr(pr.Status) {
+	case consoleclient.PullRequestStatusMerged, consoleclient.PullRequestStatusClosed:
+		return true
+	}
+	return false
+}
+
+// listPullRequests pages through the pull requests of the console until limit of them match the filter, since
+// the console can only filter by cluster and service
+func listPullRequests(consoleClient console.ConsoleClient, clusterID, serviceID *string, limit int, filter PullRequestFilter) ([]*consoleclient.PullRequestFragment, error) {
+	res := make([]*consoleclient.PullRequestFragment, 0)
+	var after *string
+	for len(res) < limit {
+		pullRequests, pageInfo, err := consoleClient.ListPullRequests(clusterID, serviceID, after, pullRequestPageSize)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, filterPullRequests(pullRequests, filter)...)
+
+		if pageInfo == nil || !pageInfo.HasNextPage || pageInfo.EndCursor == nil {
+			break
+		}
+		after = pageInfo.EndCursor
+	}
+	return lo.Subset(res, 0, uint(max(limit, 0))), nil
+}
+
+func filterPullRequests(pullRequests []*consoleclient.PullRequestFragment, filter PullRequestFilter) []*consoleclient.PullRequestFragment {
+	return lo.Filter(pullRequests, func(pr *consoleclient.PullRequestFragment, _ int) bool {
+		if pr == nil {
+			return false
+		}
+		if filter.Status != "" && !strings.EqualFold(pullRequestStatus(pr), filter.Status) {
+			return false
+		}
+		return filter.Title == nil || filter.Title.MatchString(lo.FromPtr(pr.Title))
+	})
+}
+
+// titlePattern matches the titles a PR automation generates, every liquid tag in its title template
+// matches any text since the configuration used to render it isn't known
+func titlePattern(title string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	last := 0
+	for _, loc := range liquidTag.FindAllStringIndex(title, -1) {
+		sb.WriteString(regexp.QuoteMeta(title[last:loc[0]]))
+		sb.WriteString(".*")
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(title[last:]))
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func pullRequestStatus(pr *consoleclient.PullRequestFragment) string {
+	if pr.Status == nil {
+		return "unknown"
+	}
+	return strings.ToLower(string(*pr.Status))
+}
+
+func isPullRequestStatus(status string) bool {
+	switch strings.ToUpper(status) {
+	case string(consoleclient.PullRequestStatusOpen), string(consoleclient.PullRequestStatusMerged), string(consoleclient.PullRequestStatusClosed):
+		return true
+	}
+	return false
+}
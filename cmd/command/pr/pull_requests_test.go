@@ -0,0 +1,118 @@
+package pr
+
+import (
+	"errors"
+	"testing"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/test/mocks"
+)
+
+func TestTitlePattern(t *testing.T) {
+	pattern := titlePattern("Upgrade {{ context.cluster }} to {% if context.version %}{{ context.version }}{% endif %} (v1.2)")
+
+	assert.True(t, pattern.MatchString("Upgrade prod to 1.29 (v1.2)"))
+	assert.True(t, pattern.MatchString("Upgrade prod to  (v1.2)"))
+	assert.False(t, pattern.MatchString("Upgrade prod to 1.29 (v1x2)"))
+	assert.False(t, pattern.MatchString("Rollback prod to 1.29 (v1.2)"))
+}
+
+func TestFilterPullRequests(t *testing.T) {
+	pullRequests := []*consoleclient.PullRequestFragment{
+		{ID: "pr-1", Title: lo.ToPtr("Upgrade prod"), Status: lo.ToPtr(consoleclient.PullRequestStatusOpen)},
+		{ID: "pr-2", Title: lo.ToPtr("Upgrade dev"), Status: lo.ToPtr(consoleclient.PullRequestStatusMerged)},
+		{ID: "pr-3", Title: lo.ToPtr("Add service"), Status: lo.ToPtr(consoleclient.PullRequestStatusMerged)},
+		nil,
+	}
+
+	tests := []struct {
+		name     string
+		filter   PullRequestFilter
+		expected []string
+	}{
+		{name: "no filter skips nil pull requests", expected: []string{"pr-1", "pr-2", "pr-3"}},
+		{name: "status is case insensitive", filter: PullRequestFilter{Status: "MERGED"}, expected: []string{"pr-2", "pr-3"}},
+		{name: "automation title", filter: PullRequestFilter{Title: titlePattern("Upgrade {{ context.cluster }}")}, expected: []string{"pr-1", "pr-2"}},
+		{name: "combined filters", filter: PullRequestFilter{Title: titlePattern("Upgrade {{ context.cluster }}"), Status: "merged"}, expected: []string{"pr-2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids := lo.Map(filterPullRequests(pullRequests, test.filter), func(pr *consoleclient.PullRequestFragment, _ int) string {
+				return pr.ID
+			})
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
+
+func TestPullRequestOutcomeExitCodes(t *testing.T) {
+	tests := []struct {
+		status consoleclient.PullRequestStatus
+		code   int
+	}{
+		{status: consoleclient.PullRequestStatusMerged, code: 0},
+		{status: consoleclient.PullRequestStatusClosed, code: exitPrClosed},
+		{status: consoleclient.PullRequestStatusOpen, code: exitPrFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			err := pullRequestOutcome(&consoleclient.PullRequestFragment{ID: "pr-1", Status: lo.ToPtr(test.status)})
+			if test.code == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var exitErr cli.ExitCoder
+			if assert.True(t, errors.As(err, &exitErr)) {
+				assert.Equal(t, test.code, exitErr.ExitCode())
+			}
+		})
+	}
+}
+
+func TestListPullRequestsPagesUntilLimit(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("ListPullRequests", (*string)(nil), (*string)(nil), (*string)(nil), int64(pullRequestPageSize)).Return([]*consoleclient.PullRequestFragment{
+		{ID: "pr-1", Title: lo.ToPtr("Add service")},
+		{ID: "pr-2", Title: lo.ToPtr("Upgrade prod")},
+	}, &consoleclient.PageInfoFragment{HasNextPage: true, EndCursor: lo.ToPtr("cursor-1")}, nil).Once()
+	consoleMock.On("ListPullRequests", (*string)(nil), (*string)(nil), lo.ToPtr("cursor-1"), int64(pullRequestPageSize)).Return([]*consoleclient.PullRequestFragment{
+		{ID: "pr-3", Title: lo.ToPtr("Upgrade dev")},
+		{ID: "pr-4", Title: lo.ToPtr("Upgrade staging")},
+	}, &consoleclient.PageInfoFragment{HasNextPage: true, EndCursor: lo.ToPtr("cursor-2")}, nil).Once()
+
+	pullRequests, err := listPullRequests(consoleMock, nil, nil, 2, PullRequestFilter{Title: titlePattern("Upgrade {{ context.cluster }}")})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-2", "pr-3"}, lo.Map(pullRequests, func(pr *consoleclient.PullRequestFragment, _ int) string { return pr.ID }))
+}
+
+func TestWaitForPullRequestRetriesFailedPolls(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("GetPullRequest", "pr-1").Return(nil, errors.New("bad gateway")).Twice()
+	consoleMock.On("GetPullRequest", "pr-1").Return(&consoleclient.PullRequestFragment{ID: "pr-1", Status: lo.ToPtr(consoleclient.PullRequestStatusMerged)}, nil).Once()
+
+	pr, err := waitForPullRequest(consoleMock, "pr-1", 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1", pr.ID)
+}
+
+func TestWaitForPullRequestGivesUpOnApiErrors(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("GetPullRequest", "pr-1").Return(nil, errors.New("bad gateway")).Times(maxPollRetries + 1)
+
+	_, err := waitForPullRequest(consoleMock, "pr-1", 1, 0)
+
+	var exitErr cli.ExitCoder
+	if assert.True(t, errors.As(err, &exitErr)) {
+		assert.Equal(t, exitPrAPIError, exitErr.ExitCode())
+	}
+}
@@ -0,0 +1,144 @@
+package pr
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/pr/scaffold"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func handlePrScaffold(c *cli.Context) error {
+	name := c.String("name")
+	sources := c.StringSlice("from")
+
+	variables, err := scaffoldVariables(c.StringSlice("var"))
+	if err != nil {
+		return err
+	}
+	if !c.Bool("non-interactive") {
+		if variables, err = promptScaffoldVariables(sources, variables); err != nil {
+			return err
+		}
+	}
+
+	dir := c.String("dir")
+	if dir == "" {
+		dir = filepath.Join("prautomations", name)
+	}
+
+	result, err := scaffold.Scaffold(scaffold.Options{
+		Name:      name,
+		Sources:   sources,
+		Dir:       dir,
+		Contracts: c.String("contracts"),
+		Variables: variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.Success("Wrote PR automation %s\n", result.Automation)
+	fmt.Printf("templates:\n  %s\n", strings.Join(result.Templates, "\n  "))
+	fmt.Printf("sample context: %s\n", result.Context)
+	fmt.Printf("contracts: %s\n\n", result.Contracts)
+	utils.Highlight("Check the generated files with `plural pr contracts --file %s --validate`\n", result.Contracts)
+	return nil
+}
+
+// scaffoldVariables parses name=literal pairs, the type is inferred from the literal
+func scaffoldVariables(pairs []string) ([]scaffold.Variable, error) {
+	variables := make([]scaffold.Variable, 0, len(pairs))
+	for _, pair := range pairs {
+		name, literal, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected name=literal", pair)
+		}
+		variables = append(variables, scaffold.Variable{Name: name, Literal: literal, Type: scaffold.InferType(literal)})
+	}
+	return variables, nil
+}
+
+func promptScaffoldVariables(sources []string, variables []scaffold.Variable) ([]scaffold.Variable, error) {
+	used := map[string]bool{}
+	literals := map[string]bool{}
+	for _, v := range variables {
+		used[v.Name] = true
+		literals[v.Literal] = true
+	}
+
+	candidates, err := scaffold.Candidates(sources)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]string, 0, len(candidates))
+	byOption := map[string]scaffold.Candidate{}
+	for _, candidate := range candidates {
+		if literals[candidate.Literal] {
+			continue
+		}
+		option := fmt.Sprintf("%s: %s", candidate.Key, candidate.Literal)
+		options = append(options, option)
+		byOption[option] = candidate
+	}
+
+	selected := make([]string, 0)
+	if len(options) > 0 {
+		if err := survey.AskOne(&survey.MultiSelect{
+			Message: "Which values should become configuration variables?",
+			Options: options,
+		}, &selected); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, option := range selected {
+		candidate := byOption[option]
+		variable, err := promptScaffoldVariable(candidate.Literal, scaffold.VariableName(candidate, used))
+		if err != nil {
+			return nil, err
+		}
+		used[variable.Name] = true
+		variables = append(variables, variable)
+	}
+
+	for {
+		literal := ""
+		if err := survey.AskOne(&survey.Input{
+			Message: "Any other literal to turn into a variable? (leave empty to finish)",
+		}, &literal); err != nil {
+			return nil, err
+		}
+		if literal == "" {
+			return variables, nil
+		}
+
+		variable, err := promptScaffoldVariable(literal, scaffold.VariableName(scaffold.Candidate{Literal: literal}, used))
+		if err != nil {
+			return nil, err
+		}
+		used[variable.Name] = true
+		variables = append(variables, variable)
+	}
+}
+
+func promptScaffoldVariable(literal, suggestion string) (scaffold.Variable, error) {
+	variable := scaffold.Variable{Literal: literal}
+	if err := survey.AskOne(&survey.Input{
+		Message: fmt.Sprintf("Variable name for %q:", literal),
+		Default: suggestion,
+	}, &variable.Name, survey.WithValidator(survey.Required)); err != nil {
+		return variable, err
+	}
+
+	err := survey.AskOne(&survey.Select{
+		Message: "Variable type:",
+		Options: []string{scaffold.TypeString, scaffold.TypeInt, scaffold.TypeBool},
+		Default: scaffold.InferType(literal),
+	}, &variable.Type)
+	return variable, err
+}
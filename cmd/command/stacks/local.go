@@ -0,0 +1,108 @@
+package stacks
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	gqlclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/common"
+	"github.com/pluralsh/plural-cli/pkg/config"
+	"github.com/pluralsh/plural-cli/pkg/stacks"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func (p *Plural) localCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:      "plan",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleLocalPlan, []string{"{stack}"})),
+			Usage:     "run terraform plan for a stack locally against its console managed state, without taking the state lock",
+			ArgsUsage: "{stack} [-- terraform plan args]",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "dir", Usage: "directory to check the stack out into, defaults to a temporary directory"},
+				cli.BoolFlag{Name: "keep", Usage: "keep the temporary checkout after the plan finishes"},
+			},
+		},
+	}
+}
+
+func (p *Plural) handleLocalPlan(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id, err := p.stackID(c.Args().First())
+	if err != nil {
+		return err
+	}
+	stack, err := p.ConsoleClient.GetStack(id)
+	if err != nil {
+		return err
+	}
+	stateUrls, err := stacks.GetTerraformStateUrls(p.ConsoleClient, id)
+	if err != nil {
+		return err
+	}
+	local, err := localStack(stack, stateUrls)
+	if err != nil {
+		return err
+	}
+
+	dir := c.String("dir")
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "plural-stack-"); err != nil {
+			return err
+		}
+		if !c.Bool("keep") {
+			defer os.RemoveAll(dir)
+		}
+	}
+
+	workdir, err := local.Prepare(stacks.LocalPlanOptions{
+		Dir:         dir,
+		Actor:       config.Read().Email,
+		DeployToken: p.ConsoleClient.Token(),
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.Highlight("Planning stack %s in %s\n", stack.Name, workdir)
+	return local.Plan(context.Background(), workdir, c.Args().Tail())
+}
+
+func localStack(stack *gqlclient.InfrastructureStackFragment, stateUrls *gqlclient.TerraformStateUrls) (*stacks.LocalStack, error) {
+	if stack.Type != gqlclient.StackTypeTerraform {
+		return nil, fmt.Errorf("only terraform stacks can be planned locally, %s is a %s stack", stack.Name, stack.Type)
+	}
+	if stateUrls == nil || lo.FromPtr(stateUrls.Address) == "" {
+		return nil, fmt.Errorf("stack %s has no terraform state yet, it needs at least one console run first", stack.Name)
+	}
+
+	local := &stacks.LocalStack{
+		Ref:          stack.Git.Ref,
+		Folder:       stack.Git.Folder,
+		Variables:    stack.Variables,
+		Environment:  map[string]string{},
+		Files:        map[string]string{},
+		StateAddress: lo.FromPtr(stateUrls.Address),
+	}
+	if stack.Repository != nil {
+		local.RepositoryURL = stack.Repository.URL
+	}
+	for _, env := range stack.Environment {
+		if env != nil {
+			local.Environment[env.Name] = env.Value
+		}
+	}
+	for _, file := range stack.Files {
+		if file != nil {
+			local.Files[file.Path] = file.Content
+		}
+	}
+	return local, nil
+}
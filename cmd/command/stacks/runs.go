@@ -0,0 +1,239 @@
+package stacks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gqlclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/urfave/cli"
+
+	"github.com/pluralsh/plural-cli/pkg/console"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+func (p *Plural) handleListStacks(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	stacks, err := p.listStacks()
+	if err != nil {
+		return err
+	}
+	if printed, err := utils.PrintOutput(c.String("o"), stacks); printed || err != nil {
+		return err
+	}
+
+	headers := []string{"Id", "Name", "Type", "Status", "Approval"}
+	return utils.PrintTable(stacks, headers, func(stack *gqlclient.InfrastructureStackFragment) ([]string, error) {
+		return []string{lo.FromPtr(stack.ID), stack.Name, string(stack.Type), string(stack.Status), fmt.Sprint(lo.FromPtr(stack.Approval))}, nil
+	})
+}
+
+func (p *Plural) handleDescribeStack(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id, err := p.stackID(c.Args().First())
+	if err != nil {
+		return err
+	}
+	stack, err := p.ConsoleClient.GetStack(id)
+	if err != nil {
+		return err
+	}
+	if printed, err := utils.PrintOutput(c.String("o"), stack); printed || err != nil {
+		return err
+	}
+
+	desc, err := console.DescribeStack(stack)
+	if err != nil {
+		return err
+	}
+	fmt.Print(desc)
+	return nil
+}
+
+func (p *Plural) handleListStackRuns(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id, err := p.stackID(c.Args().First())
+	if err != nil {
+		return err
+	}
+	result, err := p.ConsoleClient.ListStackRuns(id)
+	if err != nil {
+		return err
+	}
+	if result == nil || result.InfrastructureStack == nil || result.InfrastructureStack.Runs == nil {
+		return fmt.Errorf("returned objects list [ListStackRuns] is nil")
+	}
+
+	runs := lo.Filter(result.InfrastructureStack.Runs.Edges, func(edge *gqlclient.ListStackRuns_InfrastructureStack_Runs_Edges, _ int) bool {
+		return edge != nil && edge.Node != nil
+	})
+	nodes := lo.Map(runs, func(edge *gqlclient.ListStackRuns_InfrastructureStack_Runs_Edges, _ int) any {
+		return edge.Node
+	})
+	if printed, err := utils.PrintOutput(c.String("o"), nodes); printed || err != nil {
+		return err
+	}
+
+	headers := []string{"Id", "Status", "Created", "Message"}
+	return utils.PrintTable(runs, headers, func(edge *gqlclient.ListStackRuns_InfrastructureStack_Runs_Edges) ([]string, error) {
+		run := edge.Node
+		return []string{run.ID, string(run.Status), lo.FromPtr(run.InsertedAt), utils.Summarize(lo.FromPtr(run.Message), 60)}, nil
+	})
+}
+
+func (p *Plural) handleStackRunLogs(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	return followStackRun(p.ConsoleClient, c.Args().First(), c.Bool("follow"), c.Duration("interval"))
+}
+
+func (p *Plural) handleApproveStackRun(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id := c.Args().First()
+	if err := p.ConsoleClient.ApproveStackRun(id); err != nil {
+		return err
+	}
+
+	utils.Success("Approved stack run %s\n", id)
+	return nil
+}
+
+func (p *Plural) handleRestartStackRun(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	runID, err := p.ConsoleClient.RestartStackRun(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	utils.Success("Restarted stack run as %s\n", runID)
+	return nil
+}
+
+func (p *Plural) handleTriggerStackRun(c *cli.Context) error {
+	if err := p.InitConsoleClient(consoleToken, consoleURL); err != nil {
+		return err
+	}
+
+	id, err := p.stackID(c.Args().First())
+	if err != nil {
+		return err
+	}
+	runID, err := p.ConsoleClient.TriggerStackRun(id)
+	if err != nil {
+		return err
+	}
+
+	utils.Success("Triggered stack run %s\n", runID)
+	return nil
+}
+
+func (p *Plural) listStacks() ([]*gqlclient.InfrastructureStackFragment, error) {
+	result, err := p.ConsoleClient.ListStacks()
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.InfrastructureStacks == nil {
+		return nil, fmt.Errorf("returned objects list [ListStacks] is nil")
+	}
+
+	stacks := make([]*gqlclient.InfrastructureStackFragment, 0, len(result.InfrastructureStacks.Edges))
+	for _, edge := range result.InfrastructureStacks.Edges {
+		if edge != nil && edge.Node != nil {
+			stacks = append(stacks, edge.Node)
+		}
+	}
+	return stacks, nil
+}
+
+// stackID accepts either a stack name or id, names are matched against the stacks visible to the user
+func (p *Plural) stackID(stack string) (string, error) {
+	stacks, err := p.listStacks()
+	if err != nil {
+		return "", err
+	}
+
+	return resolveStackID(stacks, stack), nil
+}
+
+func resolveStackID(stacks []*gqlclient.InfrastructureStackFragment, stack string) string {
+	for _, s := range stacks {
+		if s.Name == stack && s.ID != nil {
+			return *s.ID
+		}
+	}
+
+	return stack
+}
+
+// followStackRun prints the logs of every step of a run, and if follow is set keeps polling for new logs
+// until the run reaches a terminal state
+func followStackRun(client console.ConsoleClient, id string, follow bool, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	seen := map[string]bool{}
+	for {
+		run, err := client.GetStackRun(id)
+		if err != nil {
+			return err
+		}
+		printStepLogs(run.Steps, seen)
+
+		if !follow || isTerminal(run.Status) {
+			if follow {
+				utils.Highlight("stack run %s finished with status %s\n", id, strings.ToLower(string(run.Status)))
+			}
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+func printStepLogs(steps []*gqlclient.RunStepFragment, seen map[string]bool) {
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		for _, logs := range step.Logs {
+			if logs == nil || seen[logs.ID] {
+				continue
+			}
+			if !seen[step.ID] {
+				utils.Highlight("==> %s\n", step.Name)
+				seen[step.ID] = true
+			}
+			fmt.Print(logs.Logs)
+			seen[logs.ID] = true
+		}
+	}
+}
+
+func isTerminal(status gqlclient.StackStatus) bool {
+	switch status {
+	case gqlclient.StackStatusSuccessful, gqlclient.StackStatusFailed, gqlclient.StackStatusCancelled:
+		return true
+	}
+	return false
+}
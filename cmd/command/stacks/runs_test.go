@@ -0,0 +1,34 @@
+package stacks
+
+import (
+	"testing"
+
+	gqlclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/test/mocks"
+)
+
+func TestResolveStackID(t *testing.T) {
+	stacks := []*gqlclient.InfrastructureStackFragment{
+		{ID: lo.ToPtr("stack-1"), Name: "network"},
+		{ID: lo.ToPtr("stack-2"), Name: "database"},
+	}
+
+	assert.Equal(t, "stack-2", resolveStackID(stacks, "database"))
+	assert.Equal(t, "stack-1", resolveStackID(stacks, "stack-1"))
+	assert.Equal(t, "unknown", resolveStackID(stacks, "unknown"))
+}
+
+func TestFollowStackRunPollsUntilTerminal(t *testing.T) {
+	client := mocks.NewConsoleClient(t)
+	client.On("GetStackRun", "run-1").Return(&gqlclient.StackRunFragment{ID: "run-1", Status: gqlclient.StackStatusRunning}, nil).Once()
+	client.On("GetStackRun", "run-1").Return(&gqlclient.StackRunFragment{ID: "run-1", Status: gqlclient.StackStatusSuccessful}, nil).Once()
+
+	err := followStackRun(client, "run-1", true, 1)
+
+	require.NoError(t, err)
+	client.AssertExpectations(t)
+}
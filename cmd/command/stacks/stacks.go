@@ -56,6 +56,59 @@ func Command(clients client.Plural) cli.Command {
 
 func (p *Plural) stacksCommands() []cli.Command {
 	return []cli.Command{
+		{
+			Name:   "list",
+			Action: common.LatestVersion(p.handleListStacks),
+			Usage:  "list infrastructure stacks",
+			Flags:  []cli.Flag{cli.StringFlag{Name: "o", Usage: "output format"}},
+		},
+		{
+			Name:      "describe",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleDescribeStack, []string{"{stack}"})),
+			Usage:     "describe an infrastructure stack",
+			ArgsUsage: "{stack}",
+			Flags:     []cli.Flag{cli.StringFlag{Name: "o", Usage: "output format"}},
+		},
+		{
+			Name:      "runs",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleListStackRuns, []string{"{stack}"})),
+			Usage:     "list the runs of an infrastructure stack",
+			ArgsUsage: "{stack}",
+			Flags:     []cli.Flag{cli.StringFlag{Name: "o", Usage: "output format"}},
+		},
+		{
+			Name:      "logs",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleStackRunLogs, []string{"{run-id}"})),
+			Usage:     "print the logs of a stack run",
+			ArgsUsage: "{run-id}",
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "follow, f", Usage: "keep printing new logs until the run completes"},
+				cli.DurationFlag{Name: "interval", Usage: "how often to poll for new logs when following", Value: defaultPollInterval},
+			},
+		},
+		{
+			Name:      "approve",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleApproveStackRun, []string{"{run-id}"})),
+			Usage:     "approve the plan of a stack run waiting for approval",
+			ArgsUsage: "{run-id}",
+		},
+		{
+			Name:      "restart",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleRestartStackRun, []string{"{run-id}"})),
+			Usage:     "restart a stack run",
+			ArgsUsage: "{run-id}",
+		},
+		{
+			Name:      "trigger",
+			Action:    common.LatestVersion(common.RequireArgs(p.handleTriggerStackRun, []string{"{stack}"})),
+			Usage:     "trigger a new run of an infrastructure stack",
+			ArgsUsage: "{stack}",
+		},
+		{
+			Name:        "local",
+			Usage:       "reproduce stack runs on this machine",
+			Subcommands: p.localCommands(),
+		},
 		{
 			Name:   "gen-backend",
 			Action: common.LatestVersion(p.handleGenerateBackend),
@@ -0,0 +1,110 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	plrltpl "github.com/pluralsh/plural-cli/pkg/template"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func Command() cli.Command {
+	return cli.Command{
+		Name:        "template",
+		Usage:       "renders templates locally with the same functions as the deployment agent",
+		Subcommands: commands(),
+		Category:    "CD",
+	}
+}
+
+func commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:   "functions",
+			Usage:  "lists the functions available to .tpl and .liquid templates",
+			Action: handleFunctions,
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "engine", Usage: "only list functions of this engine, one of tpl or liquid"},
+				cli.StringFlag{Name: "mode", Usage: "agent lists what the deployment agent supports, cli adds the helpers of plural up templates", Value: plrltpl.ModeAgent},
+				cli.StringFlag{Name: "o", Usage: "output format, one of json or yaml"},
+			},
+		},
+		{
+			Name:   "render",
+			Usage:  "renders a .tpl or .liquid file with explicitly supplied bindings",
+			Action: handleRender,
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "file, f", Usage: "the .tpl or .liquid file to render", Required: true},
+				cli.StringFlag{Name: "context", Usage: "yaml file with the bindings to render the template with"},
+				cli.StringFlag{Name: "mode", Usage: "agent renders exactly like the deployment agent, cli adds the helpers of plural up templates", Value: plrltpl.ModeAgent},
+			},
+		},
+	}
+}
+
+func handleFunctions(c *cli.Context) error {
+	catalog, err := plrltpl.FunctionCatalog(c.String("mode"))
+	if err != nil {
+		return err
+	}
+
+	if engine := c.String("engine"); engine != "" {
+		if engine != plrltpl.EngineTpl && engine != plrltpl.EngineLiquid {
+			return fmt.Errorf("unsupported engine %q, must be one of tpl or liquid", engine)
+		}
+		functions := make([]plrltpl.Function, 0, len(catalog.Functions))
+		for _, fn := range catalog.Functions {
+			if fn.Engine == engine {
+				functions = append(functions, fn)
+			}
+		}
+		catalog.Functions = functions
+	}
+
+	switch c.String("o") {
+	case "json":
+		utils.NewJsonPrinter(catalog).PrettyPrint()
+		return nil
+	case "yaml":
+		utils.NewYAMLPrinter(catalog).PrettyPrint()
+		return nil
+	case "":
+	default:
+		return fmt.Errorf("unsupported output format %q, must be one of json or yaml", c.String("o"))
+	}
+
+	utils.Highlight("template renderer %s, %s mode\n", catalog.Version, catalog.Mode)
+	headers := []string{"Name", "Engine", "Source", "Description"}
+	return utils.PrintTable(catalog.Functions, headers, func(fn plrltpl.Function) ([]string, error) {
+		name := fn.Name
+		if len(fn.Aliases) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(fn.Aliases, ", "))
+		}
+		return []string{name, fn.Engine, fn.Source, fn.Description}, nil
+	})
+}
+
+func handleRender(c *cli.Context) error {
+	path := c.String("file")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	bindings := map[string]interface{}{}
+	if ctx := c.String("context"); ctx != "" {
+		if err := utils.YamlFile(ctx, &bindings); err != nil {
+			return err
+		}
+	}
+
+	out, err := plrltpl.Render(path, content, bindings, c.String("mode"))
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
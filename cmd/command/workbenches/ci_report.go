@@ -0,0 +1,187 @@
+package workbenches
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxReportedFailures = 20
+	maxFailureLines     = 40
+	maxLogLines         = 80
+)
+
+var ciLogFailurePattern = regexp.MustCompile(`(?i)(^\s*--- FAIL|^\s*FAIL\b|\bFAILED\b|\berror\b|\bpanic:|\bexception\b|\bassert)`)
+
+type TestFailure struct {
+	Suite   string
+	Name    string
+	Message string
+	Output  string
+}
+
+type CIReport struct {
+	Failures []TestFailure
+	LogLines []string
+}
+
+func (r CIReport) Empty() bool {
+	return len(r.Failures) == 0 && len(r.LogLines) == 0
+}
+
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string       `xml:"name,attr"`
+	Classname string       `xml:"classname,attr"`
+	Failure   *junitResult `xml:"failure"`
+	Error     *junitResult `xml:"error"`
+	SystemOut string       `xml:"system-out"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// ParseJUnitReport returns the failed and errored test cases of a JUnit XML report,
+// the root element may be either <testsuites> or a single <testsuite>.
+func ParseJUnitReport(reader io.Reader) ([]TestFailure, error) {
+	var root junitSuite
+	if err := xml.NewDecoder(reader).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid JUnit report: %w", err)
+	}
+
+	return collectFailures(root, root.Name), nil
+}
+
+func collectFailures(suite junitSuite, name string) []TestFailure {
+	failures := make([]TestFailure, 0)
+	for _, testCase := range suite.Cases {
+		result := testCase.Failure
+		if result == nil {
+			result = testCase.Error
+		}
+		if result == nil {
+			continue
+		}
+
+		caseSuite := name
+		if testCase.Classname != "" {
+			caseSuite = testCase.Classname
+		}
+		output := strings.TrimSpace(result.Body)
+		if output == "" {
+			output = strings.TrimSpace(testCase.SystemOut)
+		}
+		failures = append(failures, TestFailure{
+			Suite:   caseSuite,
+			Name:    testCase.Name,
+			Message: strings.TrimSpace(result.Message),
+			Output:  output,
+		})
+	}
+
+	for _, child := range suite.Suites {
+		childName := child.Name
+		if childName == "" {
+			childName = name
+		}
+		failures = append(failures, collectFailures(child, childName)...)
+	}
+	return failures
+}
+
+// ParseCILog returns the lines of a CI log that look like failures, or its tail
+// when none match, so the prompt stays within a reasonable size.
+func ParseCILog(reader io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	matched := make([]string, 0)
+	tail := make([]string, 0, maxLogLines)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" {
+			continue
+		}
+		if ciLogFailurePattern.MatchString(line) && len(matched) < maxLogLines {
+			matched = append(matched, line)
+		}
+		if len(tail) == maxLogLines {
+			tail = tail[1:]
+		}
+		tail = append(tail, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(matched) > 0 {
+		return matched, nil
+	}
+	return tail, nil
+}
+
+// BuildCIFollowupPrompt summarizes a CI report into a prompt for the workbench job,
+// instructions are prepended when set.
+func BuildCIFollowupPrompt(report CIReport, instructions string) string {
+	var prompt strings.Builder
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		prompt.WriteString(instructions)
+		prompt.WriteString("\n\n")
+	} else {
+		prompt.WriteString("CI failed on this pull request. Investigate the failures below, fix them and push the changes.\n\n")
+	}
+
+	if len(report.Failures) > 0 {
+		fmt.Fprintf(&prompt, "Failing tests (%d):\n", len(report.Failures))
+		for i, failure := range report.Failures {
+			if i == maxReportedFailures {
+				fmt.Fprintf(&prompt, "\n...and %d more failing tests\n", len(report.Failures)-maxReportedFailures)
+				break
+			}
+
+			prompt.WriteString("\n- ")
+			if failure.Suite != "" {
+				prompt.WriteString(failure.Suite + ": ")
+			}
+			prompt.WriteString(failure.Name)
+			if failure.Message != "" {
+				prompt.WriteString(" (" + failure.Message + ")")
+			}
+			prompt.WriteString("\n")
+			if failure.Output != "" {
+				prompt.WriteString("```\n" + truncateLines(failure.Output, maxFailureLines) + "\n```\n")
+			}
+		}
+	}
+
+	if len(report.LogLines) > 0 {
+		if len(report.Failures) > 0 {
+			prompt.WriteString("\n")
+		}
+		prompt.WriteString("CI log excerpt:\n```\n")
+		prompt.WriteString(strings.Join(report.LogLines, "\n"))
+		prompt.WriteString("\n```\n")
+	}
+
+	return strings.TrimRight(prompt.String(), "\n")
+}
+
+func truncateLines(value string, limit int) string {
+	lines := strings.Split(value, "\n")
+	if len(lines) <= limit {
+		return value
+	}
+	return strings.Join(lines[:limit], "\n") + fmt.Sprintf("\n... (%d more lines)", len(lines)-limit)
+}
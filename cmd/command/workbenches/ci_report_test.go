@@ -0,0 +1,64 @@
+package workbenches
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJUnitReportCollectsNestedFailures(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="pkg/api">
+    <testcase name="TestOK" classname="pkg/api"/>
+    <testcase name="TestBroken" classname="pkg/api">
+      <failure message="expected 1, got 2">api_test.go:12: expected 1, got 2</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="pkg/cli">
+    <testcase name="TestPanics">
+      <error message="panic"/>
+      <system-out>panic: nil map</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	failures, err := ParseJUnitReport(strings.NewReader(report))
+
+	require.NoError(t, err)
+	assert.Equal(t, []TestFailure{
+		{Suite: "pkg/api", Name: "TestBroken", Message: "expected 1, got 2", Output: "api_test.go:12: expected 1, got 2"},
+		{Suite: "pkg/cli", Name: "TestPanics", Message: "panic", Output: "panic: nil map"},
+	}, failures)
+}
+
+func TestParseJUnitReportRejectsInvalidXML(t *testing.T) {
+	_, err := ParseJUnitReport(strings.NewReader("not xml"))
+
+	require.Error(t, err)
+}
+
+func TestParseCILogPrefersFailureLines(t *testing.T) {
+	lines, err := ParseCILog(strings.NewReader("=== RUN TestA\n--- FAIL: TestA (0.00s)\nok  pkg/b\nFAIL\tpkg/a\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--- FAIL: TestA (0.00s)", "FAIL\tpkg/a"}, lines)
+
+	lines, err = ParseCILog(strings.NewReader("step 1\nstep 2\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"step 1", "step 2"}, lines)
+}
+
+func TestBuildCIFollowupPrompt(t *testing.T) {
+	prompt := BuildCIFollowupPrompt(CIReport{
+		Failures: []TestFailure{{Suite: "pkg/api", Name: "TestBroken", Message: "expected 1, got 2", Output: "api_test.go:12"}},
+		LogLines: []string{"FAIL\tpkg/api"},
+	}, "")
+
+	assert.Equal(t, "CI failed on this pull request. Investigate the failures below, fix them and push the changes.\n\n"+
+		"Failing tests (1):\n\n- pkg/api: TestBroken (expected 1, got 2)\n```\napi_test.go:12\n```\n\n"+
+		"CI log excerpt:\n```\nFAIL\tpkg/api\n```", prompt)
+}
@@ -0,0 +1,75 @@
+package workbenches
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	hookName   = "pre-push"
+	hookMarker = "# installed by plural workbenches hooks install"
+
+	defaultHookPrompt = "New commits were pushed to this pull request. Review them and continue working on the task."
+	defaultHookDefer  = 30 * time.Second
+)
+
+const hookTemplate = `#!/bin/sh
+%s
+# git has no post-push hook, follow-ups are deferred so they run after the push lands
+while read -r local_ref local_sha remote_ref remote_sha; do
+  case "$local_sha" in
+    *[!0]*) ;;
+    *) continue ;;
+  esac
+  %s workbenches pr-followup --commit "$local_sha" --prompt %s --defer %s --skip-missing </dev/null >/dev/null 2>&1 &
+done
+exit 0
+`
+
+type HookOptions struct {
+	Prompt  string
+	DeferBy time.Duration
+	Binary  string
+	Force   bool
+}
+
+// InstallHook writes the follow-up hook into hooksDir and returns its path, an
+// existing hook not installed by plural is only replaced when Force is set.
+func InstallHook(hooksDir string, options HookOptions) (string, error) {
+	if options.DeferBy < 0 {
+		return "", fmt.Errorf("defer duration must be non-negative")
+	}
+	if strings.TrimSpace(options.Prompt) == "" {
+		options.Prompt = defaultHookPrompt
+	}
+	if options.Binary == "" {
+		options.Binary = "plural"
+	}
+
+	path := filepath.Join(hooksDir, hookName)
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err == nil && !strings.Contains(string(existing), hookMarker) && !options.Force {
+		return "", fmt.Errorf("%s already exists and was not installed by plural, rerun with --force to replace it", path)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", err
+	}
+
+	script := fmt.Sprintf(hookTemplate, hookMarker, shellQuote(options.Binary), shellQuote(options.Prompt), options.DeferBy)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of a replaced hook
+	return path, os.Chmod(path, 0755)
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
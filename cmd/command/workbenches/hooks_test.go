@@ -0,0 +1,41 @@
+package workbenches
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallHookWritesExecutableScript(t *testing.T) {
+	hooksDir := filepath.Join(t.TempDir(), "hooks")
+
+	path, err := InstallHook(hooksDir, HookOptions{Prompt: "it's pushed", DeferBy: time.Minute})
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(hooksDir, "pre-push"), path)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), hookMarker)
+	assert.Contains(t, string(data), `'plural' workbenches pr-followup --commit "$local_sha" --prompt 'it'\''s pushed' --defer 1m0s --skip-missing`)
+}
+
+func TestInstallHookKeepsForeignHooksUnlessForced(t *testing.T) {
+	hooksDir := t.TempDir()
+	path := filepath.Join(hooksDir, "pre-push")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nmake lint\n"), 0644))
+
+	_, err := InstallHook(hooksDir, HookOptions{})
+	require.Error(t, err)
+
+	_, err = InstallHook(hooksDir, HookOptions{Force: true})
+	require.NoError(t, err)
+	_, err = InstallHook(hooksDir, HookOptions{})
+	require.NoError(t, err, "reinstalling over a plural hook should not require --force")
+}
@@ -0,0 +1,201 @@
+package workbenches
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+
+	"github.com/pluralsh/plural-cli/pkg/console"
+)
+
+const (
+	defaultJobPollInterval = 5 * time.Second
+	activityPageSize       = 100
+	jobPageSize            = 100
+)
+
+type JobService struct {
+	client console.ConsoleClient
+	out    io.Writer
+}
+
+type JobFilter struct {
+	Status string
+	Limit  int64
+}
+
+type LogOptions struct {
+	Follow   bool
+	Interval time.Duration
+}
+
+func NewJobService(client console.ConsoleClient, out io.Writer) *JobService {
+	return &JobService{client: client, out: out}
+}
+
+func (s *JobService) ListJobs(workbenchID string, filter JobFilter) ([]*consoleclient.WorkbenchJobTinyFragment, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("workbench client is not configured")
+	}
+
+	status := consoleclient.WorkbenchJobStatus(strings.ToUpper(strings.TrimSpace(filter.Status)))
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("unsupported status %q", filter.Status)
+	}
+
+	// the console can't filter jobs by status, so keep paging until limit of them match
+	filtered := make([]*consoleclient.WorkbenchJobTinyFragment, 0)
+	var after *string
+	for int64(len(filtered)) < filter.Limit {
+		jobs, pageInfo, err := s.client.ListWorkbenchJobs(workbenchID, after, jobPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, job := range jobs {
+			if job == nil || (status != "" && job.Status != status) {
+				continue
+			}
+			filtered = append(filtered, job)
+		}
+
+		if pageInfo == nil || !pageInfo.HasNextPage || pageInfo.EndCursor == nil {
+			break
+		}
+		after = pageInfo.EndCursor
+	}
+	return lo.Subset(filtered, 0, uint(max(filter.Limit, 0))), nil
+}
+
+// Logs prints the activity of a job, and if follow is set keeps polling for new
+// activity until the job reaches a terminal state
+func (s *JobService) Logs(jobID string, options LogOptions) error {
+	if s.client == nil {
+		return fmt.Errorf("workbench client is not configured")
+	}
+
+	interval := options.Interval
+	if interval <= 0 {
+		interval = defaultJobPollInterval
+	}
+
+	seen := map[string]bool{}
+	var after *string
+	for {
+		// fetch the job before its activity so nothing written just before completion is missed
+		job, err := s.client.GetWorkbenchJob(jobID)
+		if err != nil {
+			return err
+		}
+
+		if after, err = s.printNewActivities(jobID, after, seen); err != nil {
+			return err
+		}
+
+		if !options.Follow || s.isTerminal(job.Status) {
+			if options.Follow {
+				fmt.Fprintf(s.out, "workbench job %s finished with status %s\n", jobID, strings.ToLower(string(job.Status)))
+			}
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (s *JobService) Prompt(jobID, prompt string) (string, error) {
+	if strings.TrimSpace(prompt) == "" {
+		return "", fmt.Errorf("prompt cannot be empty")
+	}
+	if s.client == nil {
+		return "", fmt.Errorf("workbench client is not configured")
+	}
+
+	job, err := s.client.GetWorkbenchJob(jobID)
+	if err != nil {
+		return "", err
+	}
+	if s.isTerminal(job.Status) {
+		return "", fmt.Errorf("workbench job %s is %s and can no longer receive prompts", jobID, strings.ToLower(string(job.Status)))
+	}
+
+	activityID, err := s.client.CreateWorkbenchMessage(jobID, prompt)
+	if err != nil {
+		return "", err
+	}
+	if activityID == "" {
+		return "", fmt.Errorf("console returned an empty workbench message response")
+	}
+	return activityID, nil
+}
+
+func (s *JobService) Queue(jobID string) ([]*consoleclient.QueuedPromptFragment, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("workbench client is not configured")
+	}
+
+	prompts, err := s.client.ListWorkbenchQueuedPrompts(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	queued := make([]*consoleclient.QueuedPromptFragment, 0, len(prompts))
+	for _, prompt := range prompts {
+		if prompt != nil {
+			queued = append(queued, prompt)
+		}
+	}
+	return queued, nil
+}
+
+// printNewActivities pages forward from the cursor of the last activity seen,
+// so each poll only fetches what was written since, and returns the new cursor
+func (s *JobService) printNewActivities(jobID string, after *string, seen map[string]bool) (*string, error) {
+	for {
+		activities, pageInfo, err := s.client.ListWorkbenchJobActivities(jobID, after, activityPageSize)
+		if err != nil {
+			return after, err
+		}
+		s.printActivities(activities, seen)
+
+		if pageInfo == nil {
+			return after, nil
+		}
+		if pageInfo.EndCursor != nil {
+			after = pageInfo.EndCursor
+		}
+		if !pageInfo.HasNextPage {
+			return after, nil
+		}
+	}
+}
+
+func (s *JobService) printActivities(activities []*consoleclient.WorkbenchJobActivityFragment, seen map[string]bool) {
+	for _, activity := range activities {
+		if activity == nil || seen[activity.ID] {
+			continue
+		}
+		seen[activity.ID] = true
+
+		fmt.Fprintf(s.out, "[%s] ", strings.ToLower(string(activity.Type)))
+		if prompt := activity.GetPrompt(); prompt != nil && *prompt != "" {
+			fmt.Fprintln(s.out, strings.TrimSpace(*prompt))
+		} else {
+			fmt.Fprintln(s.out)
+		}
+		if output := activity.GetResult().GetOutput(); output != nil && *output != "" {
+			fmt.Fprintln(s.out, strings.TrimSpace(*output))
+		}
+	}
+}
+
+func (s *JobService) isTerminal(status consoleclient.WorkbenchJobStatus) bool {
+	switch status {
+	case consoleclient.WorkbenchJobStatusSuccessful, consoleclient.WorkbenchJobStatusFailed, consoleclient.WorkbenchJobStatusCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package workbenches
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/test/mocks"
+)
+
+func TestJobServiceListJobsFiltersByStatus(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("ListWorkbenchJobs", "workbench-1", (*string)(nil), int64(jobPageSize)).Return([]*consoleclient.WorkbenchJobTinyFragment{
+		{ID: "job-1", Status: consoleclient.WorkbenchJobStatusRunning},
+		{ID: "job-2", Status: consoleclient.WorkbenchJobStatusSuccessful},
+		nil,
+		{ID: "job-3", Status: consoleclient.WorkbenchJobStatusRunning},
+	}, &consoleclient.PageInfoFragment{}, nil).Once()
+
+	jobs, err := NewJobService(consoleMock, &bytes.Buffer{}).ListJobs("workbench-1", JobFilter{Status: "running", Limit: 50})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job-1", "job-3"}, lo.Map(jobs, func(job *consoleclient.WorkbenchJobTinyFragment, _ int) string { return job.ID }))
+}
+
+func TestJobServiceListJobsPagesUntilLimitMatch(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("ListWorkbenchJobs", "workbench-1", (*string)(nil), int64(jobPageSize)).Return([]*consoleclient.WorkbenchJobTinyFragment{
+		{ID: "job-1", Status: consoleclient.WorkbenchJobStatusSuccessful},
+		{ID: "job-2", Status: consoleclient.WorkbenchJobStatusRunning},
+	}, &consoleclient.PageInfoFragment{HasNextPage: true, EndCursor: lo.ToPtr("cursor-1")}, nil).Once()
+	consoleMock.On("ListWorkbenchJobs", "workbench-1", lo.ToPtr("cursor-1"), int64(jobPageSize)).Return([]*consoleclient.WorkbenchJobTinyFragment{
+		{ID: "job-3", Status: consoleclient.WorkbenchJobStatusRunning},
+		{ID: "job-4", Status: consoleclient.WorkbenchJobStatusRunning},
+	}, &consoleclient.PageInfoFragment{HasNextPage: true, EndCursor: lo.ToPtr("cursor-2")}, nil).Once()
+
+	jobs, err := NewJobService(consoleMock, &bytes.Buffer{}).ListJobs("workbench-1", JobFilter{Status: "running", Limit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job-2", "job-3"}, lo.Map(jobs, func(job *consoleclient.WorkbenchJobTinyFragment, _ int) string { return job.ID }))
+}
+
+func TestJobServiceListJobsRejectsUnknownStatus(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+
+	_, err := NewJobService(consoleMock, &bytes.Buffer{}).ListJobs("workbench-1", JobFilter{Status: "done"})
+
+	require.EqualError(t, err, `unsupported status "done"`)
+	consoleMock.AssertNotCalled(t, "ListWorkbenchJobs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobServiceLogsFollowsUntilJobCompletes(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("GetWorkbenchJob", "job-1").Return(&consoleclient.WorkbenchJobTinyFragment{ID: "job-1", Status: consoleclient.WorkbenchJobStatusRunning}, nil).Once()
+	consoleMock.On("GetWorkbenchJob", "job-1").Return(&consoleclient.WorkbenchJobTinyFragment{ID: "job-1", Status: consoleclient.WorkbenchJobStatusSuccessful}, nil).Once()
+	consoleMock.On("ListWorkbenchJobActivities", "job-1", (*string)(nil), int64(activityPageSize)).Return(
+		[]*consoleclient.WorkbenchJobActivityFragment{{ID: "activity-1", Type: "USER", Prompt: lo.ToPtr("fix the build")}},
+		&consoleclient.PageInfoFragment{HasNextPage: true, EndCursor: lo.ToPtr("cursor-1")}, nil).Once()
+	consoleMock.On("ListWorkbenchJobActivities", "job-1", lo.ToPtr("cursor-1"), int64(activityPageSize)).Return(
+		[]*consoleclient.WorkbenchJobActivityFragment{{ID: "activity-2", Type: "AGENT", Prompt: lo.ToPtr("looking at the logs")}},
+		&consoleclient.PageInfoFragment{EndCursor: lo.ToPtr("cursor-2")}, nil).Once()
+	consoleMock.On("ListWorkbenchJobActivities", "job-1", lo.ToPtr("cursor-2"), int64(activityPageSize)).Return(
+		[]*consoleclient.WorkbenchJobActivityFragment{{ID: "activity-3", Type: "AGENT", Prompt: lo.ToPtr("pushed a fix")}},
+		&consoleclient.PageInfoFragment{EndCursor: lo.ToPtr("cursor-3")}, nil).Once()
+	out := &bytes.Buffer{}
+
+	err := NewJobService(consoleMock, out).Logs("job-1", LogOptions{Follow: true, Interval: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, "[user] fix the build\n[agent] looking at the logs\n[agent] pushed a fix\nworkbench job job-1 finished with status successful\n", out.String())
+}
+
+func TestJobServicePromptRejectsFinishedJob(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("GetWorkbenchJob", "job-1").Return(&consoleclient.WorkbenchJobTinyFragment{ID: "job-1", Status: consoleclient.WorkbenchJobStatusFailed}, nil).Once()
+
+	_, err := NewJobService(consoleMock, &bytes.Buffer{}).Prompt("job-1", "try again")
+
+	require.EqualError(t, err, "workbench job job-1 is failed and can no longer receive prompts")
+	consoleMock.AssertNotCalled(t, "CreateWorkbenchMessage", mock.Anything, mock.Anything)
+}
+
+func TestJobServicePromptSendsMessage(t *testing.T) {
+	consoleMock := mocks.NewConsoleClient(t)
+	consoleMock.On("GetWorkbenchJob", "job-1").Return(&consoleclient.WorkbenchJobTinyFragment{ID: "job-1", Status: consoleclient.WorkbenchJobStatusRunning}, nil).Once()
+	consoleMock.On("CreateWorkbenchMessage", "job-1", "try again").Return("", errors.New("boom")).Once()
+
+	_, err := NewJobService(consoleMock, &bytes.Buffer{}).Prompt("job-1", "try again")
+
+	require.EqualError(t, err, "boom")
+}
@@ -0,0 +1,171 @@
+package workbenches
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
+)
+
+// PullRequestLookup finds the pull request containing a commit through the provider API,
+// used when the commit subject does not reference one.
+type PullRequestLookup interface {
+	PullRequestNumber(provider ProviderName, repositoryURL, sha string) (string, error)
+}
+
+type APIPullRequestLookup struct {
+	client *http.Client
+	hosts  []config.SCMHost
+}
+
+type apiPullRequest struct {
+	Number int    `json:"number"`
+	IID    int    `json:"iid"`
+	State  string `json:"state"`
+}
+
+// number returns the pull request number shown in URLs, which GitLab calls the iid
+func (p apiPullRequest) number() int {
+	if p.IID != 0 {
+		return p.IID
+	}
+	return p.Number
+}
+
+func (p apiPullRequest) open() bool {
+	return p.State == "open" || p.State == "opened"
+}
+
+var defaultTokenEnv = map[ProviderName]string{
+	ProviderGitHub: "GITHUB_TOKEN",
+	ProviderGitLab: "GITLAB_TOKEN",
+	ProviderGitea:  "GITEA_TOKEN",
+}
+
+func NewAPIPullRequestLookup(hosts []config.SCMHost) *APIPullRequestLookup {
+	return &APIPullRequestLookup{client: &http.Client{Timeout: 30 * time.Second}, hosts: hosts}
+}
+
+func (l *APIPullRequestLookup) PullRequestNumber(provider ProviderName, repositoryURL, sha string) (string, error) {
+	parsed, err := url.Parse(repositoryURL)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse repository URL %q: %w", repositoryURL, err)
+	}
+	repository := strings.Trim(parsed.Path, "/")
+	host := l.host(parsed.Hostname())
+
+	var request *http.Request
+	switch provider {
+	case ProviderGitHub:
+		api := host.APIURL
+		if api == "" && strings.EqualFold(parsed.Hostname(), "github.com") {
+			api = "https://api.github.com"
+		} else if api == "" {
+			api = "https://" + parsed.Host + "/api/v3"
+		}
+		request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/repos/%s/commits/%s/pulls", strings.TrimRight(api, "/"), repository, sha), nil)
+		if err == nil {
+			request.Header.Set("Accept", "application/vnd.github+json")
+		}
+	case ProviderGitLab:
+		api := host.APIURL
+		if api == "" {
+			api = "https://" + parsed.Host + "/api/v4"
+		}
+		request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/projects/%s/repository/commits/%s/merge_requests", strings.TrimRight(api, "/"), url.PathEscape(repository), sha), nil)
+	case ProviderGitea:
+		api := host.APIURL
+		if api == "" {
+			api = "https://" + parsed.Host + "/api/v1"
+		}
+		request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/repos/%s/commits/%s/pull", strings.TrimRight(api, "/"), repository, sha), nil)
+	default:
+		return "", fmt.Errorf("looking up pull requests by commit is not supported for %s", provider)
+	}
+	if err != nil {
+		return "", err
+	}
+	l.authorize(request, provider, host)
+
+	response, err := l.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s API returned %s looking up commit %s", provider, response.Status, sha)
+	}
+
+	pullRequests := make([]apiPullRequest, 0)
+	if provider == ProviderGitea {
+		var pullRequest apiPullRequest
+		if err := json.NewDecoder(response.Body).Decode(&pullRequest); err != nil {
+			return "", err
+		}
+		pullRequests = append(pullRequests, pullRequest)
+	} else if err := json.NewDecoder(response.Body).Decode(&pullRequests); err != nil {
+		return "", err
+	}
+
+	number, found := l.pick(pullRequests)
+	if !found {
+		return "", fmt.Errorf("no %s pull request contains commit %s", provider, sha)
+	}
+	return number, nil
+}
+
+// pick prefers open pull requests, since a commit can belong to several
+func (l *APIPullRequestLookup) pick(pullRequests []apiPullRequest) (string, bool) {
+	var picked *apiPullRequest
+	for i := range pullRequests {
+		pullRequest := &pullRequests[i]
+		if pullRequest.number() == 0 {
+			continue
+		}
+		if picked == nil || (pullRequest.open() && !picked.open()) {
+			picked = pullRequest
+		}
+	}
+	if picked == nil {
+		return "", false
+	}
+
+	return strconv.Itoa(picked.number()), true
+}
+
+func (l *APIPullRequestLookup) authorize(request *http.Request, provider ProviderName, host config.SCMHost) {
+	env := host.TokenEnv
+	if env == "" {
+		env = defaultTokenEnv[provider]
+	}
+	token := os.Getenv(env)
+	if token == "" {
+		return
+	}
+
+	switch provider {
+	case ProviderGitLab:
+		request.Header.Set("PRIVATE-TOKEN", token)
+	case ProviderGitea:
+		request.Header.Set("Authorization", "token "+token)
+	default:
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+func (l *APIPullRequestLookup) host(name string) config.SCMHost {
+	for _, host := range l.hosts {
+		if strings.EqualFold(host.Host, name) {
+			return host
+		}
+	}
+
+	return config.SCMHost{}
+}
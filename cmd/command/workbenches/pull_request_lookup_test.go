@@ -0,0 +1,51 @@
+package workbenches
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
+)
+
+func TestAPIPullRequestLookupPrefersOpenMergeRequests(t *testing.T) {
+	t.Setenv("SELF_HOSTED_TOKEN", "secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fproject/repository/commits/abc123/merge_requests", r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		_, _ = w.Write([]byte(`[{"iid": 3, "state": "merged"}, {"iid": 4, "state": "opened"}]`))
+	}))
+	defer server.Close()
+	lookup := NewAPIPullRequestLookup([]config.SCMHost{{
+		Host:     "gitlab.example.com",
+		Provider: "gitlab",
+		APIURL:   server.URL + "/api/v4",
+		TokenEnv: "SELF_HOSTED_TOKEN",
+	}})
+
+	number, err := lookup.PullRequestNumber(ProviderGitLab, "https://gitlab.example.com/group/project", "abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "4", number)
+}
+
+func TestAPIPullRequestLookupReportsMissingPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	lookup := NewAPIPullRequestLookup([]config.SCMHost{{Host: "github.example.com", APIURL: server.URL}})
+
+	_, err := lookup.PullRequestNumber(ProviderGitHub, "https://github.example.com/team/project", "abc123")
+
+	require.EqualError(t, err, "no github pull request contains commit abc123")
+}
+
+func TestAPIPullRequestLookupRejectsUnsupportedProvider(t *testing.T) {
+	_, err := NewAPIPullRequestLookup(nil).PullRequestNumber(ProviderAzureDevOps, "https://dev.azure.com/org/project/_git/repo", "abc123")
+
+	require.EqualError(t, err, "looking up pull requests by commit is not supported for azure-devops")
+}
@@ -1,6 +1,7 @@
 package workbenches
 
 import (
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -8,10 +9,13 @@ import (
 type ProviderName string
 
 const (
-	ProviderAuto      ProviderName = "auto"
-	ProviderGitHub    ProviderName = "github"
-	ProviderGitLab    ProviderName = "gitlab"
-	ProviderBitbucket ProviderName = "bitbucket"
+	ProviderAuto            ProviderName = "auto"
+	ProviderGitHub          ProviderName = "github"
+	ProviderGitLab          ProviderName = "gitlab"
+	ProviderBitbucket       ProviderName = "bitbucket"
+	ProviderBitbucketServer ProviderName = "bitbucket-server"
+	ProviderAzureDevOps     ProviderName = "azure-devops"
+	ProviderGitea           ProviderName = "gitea"
 )
 
 type PullRequestProvider interface {
@@ -22,39 +26,66 @@ type PullRequestProvider interface {
 }
 
 type pullRequestProvider struct {
-	name       ProviderName
-	hostMarker string
-	path       string
-	patterns   []*regexp.Regexp
+	name        ProviderName
+	hostMarkers []string
+	path        string
+	patterns    []*regexp.Regexp
+	// url builds pull request URLs for providers whose layout is not repositoryURL + path + number
+	url func(repositoryURL, number string) string
 }
 
 func defaultPullRequestProviders() []PullRequestProvider {
 	return []PullRequestProvider{
 		pullRequestProvider{
-			name:       ProviderGitHub,
-			hostMarker: "github",
-			path:       "/pull/",
+			name:        ProviderGitHub,
+			hostMarkers: []string{"github"},
+			path:        "/pull/",
 			patterns: []*regexp.Regexp{
 				regexp.MustCompile(`\(#([0-9]+)\)`),
 				regexp.MustCompile(`(?i)merge pull request #([0-9]+)\b`),
 			},
 		},
 		pullRequestProvider{
-			name:       ProviderGitLab,
-			hostMarker: "gitlab",
-			path:       "/-/merge_requests/",
+			name:        ProviderGitLab,
+			hostMarkers: []string{"gitlab"},
+			path:        "/-/merge_requests/",
 			patterns: []*regexp.Regexp{
 				regexp.MustCompile(`!([0-9]+)\b`),
 			},
 		},
 		pullRequestProvider{
-			name:       ProviderBitbucket,
-			hostMarker: "bitbucket",
-			path:       "/pull-requests/",
+			name:        ProviderBitbucket,
+			hostMarkers: []string{"bitbucket"},
+			path:        "/pull-requests/",
 			patterns: []*regexp.Regexp{
 				regexp.MustCompile(`(?i)pull request #([0-9]+)\b`),
 			},
 		},
+		// Bitbucket Server and Data Center hosts can't be told apart from their name, so they
+		// are only selected explicitly or through a configured host mapping
+		pullRequestProvider{
+			name: ProviderBitbucketServer,
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`(?i)pull request #([0-9]+)\b`),
+			},
+			url: bitbucketServerPullRequestURL,
+		},
+		pullRequestProvider{
+			name:        ProviderAzureDevOps,
+			hostMarkers: []string{"dev.azure.com", "visualstudio.com"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`(?i)merged PR ([0-9]+)\b`),
+			},
+			url: azureDevOpsPullRequestURL,
+		},
+		pullRequestProvider{
+			name:        ProviderGitea,
+			hostMarkers: []string{"gitea", "codeberg"},
+			path:        "/pulls/",
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`\(#([0-9]+)\)`),
+			},
+		},
 	}
 }
 
@@ -63,7 +94,14 @@ func (p pullRequestProvider) Name() ProviderName {
 }
 
 func (p pullRequestProvider) Supports(host string) bool {
-	return strings.Contains(strings.ToLower(host), p.hostMarker)
+	host = strings.ToLower(host)
+	for _, marker := range p.hostMarkers {
+		if strings.Contains(host, marker) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (p pullRequestProvider) PullRequestNumber(subject string) (string, bool) {
@@ -79,5 +117,47 @@ func (p pullRequestProvider) PullRequestNumber(subject string) (string, bool) {
 }
 
 func (p pullRequestProvider) PullRequestURL(repositoryURL, number string) string {
+	if p.url != nil {
+		return p.url(repositoryURL, number)
+	}
+
 	return repositoryURL + p.path + number
 }
+
+// bitbucketServerPullRequestURL maps clone URLs such as https://host/scm/PROJ/repo onto
+// https://host/projects/PROJ/repos/repo/pull-requests/N
+func bitbucketServerPullRequestURL(repositoryURL, number string) string {
+	parsed, err := url.Parse(repositoryURL)
+	if err != nil {
+		return repositoryURL + "/pull-requests/" + number
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "scm" {
+		segments = segments[1:]
+	}
+	if len(segments) != 2 {
+		return repositoryURL + "/pull-requests/" + number
+	}
+
+	parsed.Path = "/projects/" + segments[0] + "/repos/" + segments[1] + "/pull-requests/" + number
+	return parsed.String()
+}
+
+// azureDevOpsPullRequestURL maps the https and ssh clone URL layouts onto
+// https://dev.azure.com/org/project/_git/repo/pullrequest/N
+func azureDevOpsPullRequestURL(repositoryURL, number string) string {
+	parsed, err := url.Parse(repositoryURL)
+	if err != nil {
+		return repositoryURL + "/pullrequest/" + number
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	// ssh remotes look like git@ssh.dev.azure.com:v3/org/project/repo, or vs-ssh.visualstudio.com for older orgs
+	if strings.Contains(strings.ToLower(parsed.Hostname()), "ssh") && len(segments) == 4 && segments[0] == "v3" {
+		parsed.Host = "dev.azure.com"
+		parsed.Path = "/" + strings.Join([]string{segments[1], segments[2], "_git", segments[3]}, "/")
+	}
+
+	return strings.TrimRight(parsed.String(), "/") + "/pullrequest/" + number
+}
@@ -5,6 +5,7 @@ import gitutils "github.com/pluralsh/plural-cli/pkg/utils/git"
 type PullRequestRepository interface {
 	CommitSubject(ref string) (string, error)
 	RemoteURL() (string, error)
+	CommitSHA(ref string) (string, error)
 }
 
 type GitPullRequestRepository struct{}
@@ -16,3 +17,7 @@ func (GitPullRequestRepository) CommitSubject(ref string) (string, error) {
 func (GitPullRequestRepository) RemoteURL() (string, error) {
 	return gitutils.GitRaw("remote", "get-url", "origin")
 }
+
+func (GitPullRequestRepository) CommitSHA(ref string) (string, error) {
+	return gitutils.GitRaw("rev-parse", "--verify", ref+"^{commit}")
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/samber/lo"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
 )
 
 type PullRequestOptions struct {
@@ -13,11 +15,15 @@ type PullRequestOptions struct {
 	Commit   string
 	BaseURL  string
 	Provider string
+	// LookupByCommit falls back to the provider API when the commit subject has no pull request number
+	LookupByCommit bool
 }
 
 type PullRequestResolver struct {
 	repository PullRequestRepository
 	providers  []PullRequestProvider
+	hosts      []config.SCMHost
+	lookup     PullRequestLookup
 }
 
 type repositoryAddress struct {
@@ -36,6 +42,21 @@ func NewPullRequestResolver(repository PullRequestRepository) *PullRequestResolv
 	}
 }
 
+// WithHosts maps the given hosts onto their providers ahead of host name inference,
+// and uses them to configure API lookups.
+func (r *PullRequestResolver) WithHosts(hosts []config.SCMHost) *PullRequestResolver {
+	r.hosts = hosts
+	if r.lookup == nil {
+		r.lookup = NewAPIPullRequestLookup(hosts)
+	}
+	return r
+}
+
+func (r *PullRequestResolver) WithLookup(lookup PullRequestLookup) *PullRequestResolver {
+	r.lookup = lookup
+	return r
+}
+
 func (r *PullRequestResolver) Resolve(options PullRequestOptions) (string, error) {
 	if options.URL != "" && options.Commit != "" {
 		return "", fmt.Errorf("url and commit cannot be used together")
@@ -94,15 +115,44 @@ func (r *PullRequestResolver) Resolve(options PullRequestOptions) (string, error
 		}
 	}
 
+	repositoryURL := strings.TrimRight(address.url, "/")
 	number, found := provider.PullRequestNumber(subject)
+	if !found && options.LookupByCommit {
+		number, err = r.lookupByCommit(provider, repositoryURL, ref)
+		if err != nil {
+			return "", err
+		}
+		found = true
+	}
 	if !found {
 		return "", fmt.Errorf("commit %q does not identify a %s pull request", ref, provider.Name())
 	}
 
-	return provider.PullRequestURL(strings.TrimRight(address.url, "/"), number), nil
+	return provider.PullRequestURL(repositoryURL, number), nil
+}
+
+func (r *PullRequestResolver) lookupByCommit(provider PullRequestProvider, repositoryURL, ref string) (string, error) {
+	if r.lookup == nil {
+		return "", fmt.Errorf("pull request lookup is not configured")
+	}
+
+	sha, err := r.repository.CommitSHA(ref)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve commit %q: %w", ref, err)
+	}
+
+	return r.lookup.PullRequestNumber(provider.Name(), repositoryURL, sha)
 }
 
 func (r *PullRequestResolver) provider(name, host string) (PullRequestProvider, error) {
+	if name == "" || name == string(ProviderAuto) {
+		if mapped, found := lo.Find(r.hosts, func(mapping config.SCMHost) bool {
+			return strings.EqualFold(mapping.Host, host)
+		}); found {
+			name = mapped.Provider
+		}
+	}
+
 	if name == "" || name == string(ProviderAuto) {
 		provider, found := lo.Find(r.providers, func(provider PullRequestProvider) bool {
 			return provider.Supports(host)
@@ -174,8 +224,14 @@ func (*PullRequestResolver) parseRepositoryAddress(raw string) (repositoryAddres
 		return repositoryAddress{}, fmt.Errorf("origin URL %q does not contain a repository path", raw)
 	}
 
+	// ports of ssh remotes belong to the ssh daemon rather than the web interface
+	host := parsed.Host
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		host = parsed.Hostname()
+	}
+
 	return repositoryAddress{
-		url:  "https://" + parsed.Host + "/" + path,
+		url:  "https://" + host + "/" + path,
 		host: strings.ToLower(parsed.Hostname()),
 	}, nil
 }
@@ -6,11 +6,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
 )
 
 type fakePullRequestRepository struct {
 	subject        string
 	remote         string
+	sha            string
 	subjectErr     error
 	remoteErr      error
 	requestedRef   string
@@ -29,6 +32,22 @@ func (f *fakePullRequestRepository) RemoteURL() (string, error) {
 	return f.remote, f.remoteErr
 }
 
+func (f *fakePullRequestRepository) CommitSHA(ref string) (string, error) {
+	return f.sha, nil
+}
+
+type fakePullRequestLookup struct {
+	number        string
+	provider      ProviderName
+	repositoryURL string
+	sha           string
+}
+
+func (f *fakePullRequestLookup) PullRequestNumber(provider ProviderName, repositoryURL, sha string) (string, error) {
+	f.provider, f.repositoryURL, f.sha = provider, repositoryURL, sha
+	return f.number, nil
+}
+
 func TestPullRequestResolverUsesExplicitURL(t *testing.T) {
 	repository := &fakePullRequestRepository{subjectErr: errors.New("should not be called")}
 	resolver := NewPullRequestResolver(repository)
@@ -91,6 +110,24 @@ func TestPullRequestResolverInfersProviderURL(t *testing.T) {
 			remote:   "https://bitbucket.org/team/project.git",
 			expected: "https://bitbucket.org/team/project/pull-requests/19",
 		},
+		{
+			name:     "Azure DevOps pull request over HTTPS",
+			subject:  "Merged PR 42: Fix the pipeline",
+			remote:   "https://org@dev.azure.com/org/project/_git/repo",
+			expected: "https://dev.azure.com/org/project/_git/repo/pullrequest/42",
+		},
+		{
+			name:     "Azure DevOps pull request over SSH",
+			subject:  "Merged PR 43: Fix the pipeline",
+			remote:   "git@ssh.dev.azure.com:v3/org/project/repo",
+			expected: "https://dev.azure.com/org/project/_git/repo/pullrequest/43",
+		},
+		{
+			name:     "Gitea merge commit",
+			subject:  "Merge pull request 'Fix the build' (#7) from fix into main",
+			remote:   "git@gitea.example.com:team/project.git",
+			expected: "https://gitea.example.com/team/project/pulls/7",
+		},
 	}
 
 	for _, tt := range tests {
@@ -152,9 +189,68 @@ func TestPullRequestResolverUsesProviderSpecificCommitPattern(t *testing.T) {
 func TestPullRequestResolverRejectsUnsupportedProvider(t *testing.T) {
 	resolver := NewPullRequestResolver(&fakePullRequestRepository{})
 
-	_, err := resolver.Resolve(PullRequestOptions{Provider: "azure-devops"})
+	_, err := resolver.Resolve(PullRequestOptions{Provider: "perforce"})
+
+	require.EqualError(t, err, `unsupported source control provider "perforce"`)
+}
+
+func TestPullRequestResolverUsesConfiguredHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		subject  string
+		remote   string
+		provider string
+		expected string
+	}{
+		{
+			name:     "GitHub Enterprise on a custom domain",
+			subject:  "Fix issue (#12)",
+			remote:   "git@code.example.com:team/project.git",
+			provider: "github",
+			expected: "https://code.example.com/team/project/pull/12",
+		},
+		{
+			name:     "Bitbucket Server over SSH",
+			subject:  "Pull request #5: Fix issue",
+			remote:   "ssh://git@code.example.com:7999/proj/repo.git",
+			provider: "bitbucket-server",
+			expected: "https://code.example.com/projects/proj/repos/repo/pull-requests/5",
+		},
+		{
+			name:     "Bitbucket Server over HTTPS",
+			subject:  "Merge pull request #6 in PROJ/repo from fix to main",
+			remote:   "https://code.example.com/scm/PROJ/repo.git",
+			provider: "bitbucket-server",
+			expected: "https://code.example.com/projects/PROJ/repos/repo/pull-requests/6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewPullRequestResolver(&fakePullRequestRepository{subject: tt.subject, remote: tt.remote}).
+				WithHosts([]config.SCMHost{{Host: "Code.Example.com", Provider: tt.provider}})
+
+			result, err := resolver.Resolve(PullRequestOptions{})
 
-	require.EqualError(t, err, `unsupported source control provider "azure-devops"`)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestPullRequestResolverLooksUpPullRequestByCommit(t *testing.T) {
+	lookup := &fakePullRequestLookup{number: "31"}
+	repository := &fakePullRequestRepository{subject: "ordinary commit", remote: "git@github.com:team/project.git", sha: "abc123"}
+	resolver := NewPullRequestResolver(repository).WithLookup(lookup)
+
+	_, err := resolver.Resolve(PullRequestOptions{})
+	require.EqualError(t, err, `commit "HEAD" does not identify a github pull request`)
+
+	result, err := resolver.Resolve(PullRequestOptions{LookupByCommit: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/team/project/pull/31", result)
+	assert.Equal(t, &fakePullRequestLookup{number: "31", provider: ProviderGitHub, repositoryURL: "https://github.com/team/project", sha: "abc123"}, lookup)
 }
 
 func TestPullRequestResolverErrors(t *testing.T) {
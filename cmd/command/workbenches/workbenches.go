@@ -4,13 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	consoleclient "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
 	"github.com/urfave/cli"
 
 	pluralclient "github.com/pluralsh/plural-cli/pkg/client"
 	"github.com/pluralsh/plural-cli/pkg/common"
+	"github.com/pluralsh/plural-cli/pkg/config"
 	"github.com/pluralsh/plural-cli/pkg/utils"
+	gitutils "github.com/pluralsh/plural-cli/pkg/utils/git"
 )
 
 type outputFormat string
@@ -63,61 +68,353 @@ func (w *Workbenches) Command() cli.Command {
 				Destination: &w.consoleURL,
 			},
 		},
-		Subcommands: []cli.Command{w.prFollowupCommand()},
+		Subcommands: []cli.Command{
+			w.prFollowupCommand(),
+			w.listCommand(),
+			w.jobsCommand(),
+			w.logsCommand(),
+			w.promptCommand(),
+			w.queueCommand(),
+			w.ciCommand(),
+			w.hooksCommand(),
+		},
 	}
 }
 
-func (w *Workbenches) prFollowupCommand() cli.Command {
+func (w *Workbenches) listCommand() cli.Command {
 	return cli.Command{
-		Name:   "pr-followup",
-		Usage:  "send a follow-up prompt to the workbench job associated with a pull request",
-		Action: common.LatestVersion(w.handlePRFollowup),
+		Name:   "list",
+		Usage:  "list workbenches",
+		Action: common.LatestVersion(w.handleList),
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "limit",
+				Usage: "maximum number of workbenches to fetch",
+				Value: 100,
+			},
+		},
+	}
+}
+
+func (w *Workbenches) jobsCommand() cli.Command {
+	return cli.Command{
+		Name:      "jobs",
+		Usage:     "list the jobs of a workbench",
+		ArgsUsage: "{workbench-id}",
+		Action:    common.LatestVersion(common.RequireArgs(w.handleJobs, []string{"{workbench-id}"})),
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "status",
+				Usage: "only show jobs with this status (pending, running, successful, failed, or cancelled)",
+			},
+			cli.IntFlag{
+				Name:  "limit",
+				Usage: "maximum number of matching jobs to show",
+				Value: 100,
+			},
+		},
+	}
+}
+
+func (w *Workbenches) logsCommand() cli.Command {
+	return cli.Command{
+		Name:      "logs",
+		Usage:     "print the activity of a workbench job",
+		ArgsUsage: "{job-id}",
+		Action:    common.LatestVersion(common.RequireArgs(w.handleLogs, []string{"{job-id}"})),
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "follow, f",
+				Usage: "keep printing new activity until the job completes",
+			},
+			cli.DurationFlag{
+				Name:  "interval",
+				Usage: "how often to poll for new activity when following",
+				Value: defaultJobPollInterval,
+			},
+		},
+	}
+}
+
+func (w *Workbenches) promptCommand() cli.Command {
+	return cli.Command{
+		Name:      "prompt",
+		Usage:     "send a prompt to a running workbench job",
+		ArgsUsage: "{job-id}",
+		Action:    common.LatestVersion(common.RequireArgs(w.handlePrompt, []string{"{job-id}"})),
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:     "prompt",
-				Usage:    "follow-up prompt",
+				Usage:    "prompt to send",
 				Required: true,
 			},
-			cli.StringFlag{
-				Name:  "url",
-				Usage: "pull request URL; bypasses commit inference",
+		},
+	}
+}
+
+func (w *Workbenches) queueCommand() cli.Command {
+	return cli.Command{
+		Name:  "queue",
+		Usage: "inspect and cancel prompts queued for a workbench job",
+		Subcommands: []cli.Command{
+			{
+				Name:      "list",
+				Usage:     "list the prompts queued for a workbench job",
+				ArgsUsage: "{job-id}",
+				Action:    common.LatestVersion(common.RequireArgs(w.handleQueueList, []string{"{job-id}"})),
 			},
-			cli.StringFlag{
-				Name:  "commit",
-				Usage: "commit or ref whose subject identifies the pull request (defaults to HEAD)",
+			{
+				Name:      "cancel",
+				Usage:     "cancel a queued prompt before it is delivered",
+				ArgsUsage: "{prompt-id}",
+				Action:    common.LatestVersion(common.RequireArgs(w.handleQueueCancel, []string{"{prompt-id}"})),
 			},
+		},
+	}
+}
+
+func (w *Workbenches) prFollowupCommand() cli.Command {
+	return cli.Command{
+		Name:   "pr-followup",
+		Usage:  "send a follow-up prompt to the workbench job associated with a pull request",
+		Action: common.LatestVersion(w.handlePRFollowup),
+		Flags: append([]cli.Flag{
 			cli.StringFlag{
-				Name:  "base-url",
-				Usage: "repository web URL used to construct the pull request URL",
+				Name:     "prompt",
+				Usage:    "follow-up prompt",
+				Required: true,
 			},
-			cli.StringFlag{
-				Name:  "provider",
-				Usage: "source control provider (auto, github, gitlab, or bitbucket)",
-				Value: string(ProviderAuto),
+		}, followupFlags()...),
+	}
+}
+
+func (w *Workbenches) ciCommand() cli.Command {
+	return cli.Command{
+		Name:   "ci",
+		Usage:  "summarize CI failures into a follow-up prompt for the workbench job associated with a pull request",
+		Action: common.LatestVersion(w.handleCI),
+		Flags: append([]cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "junit",
+				Usage: "JUnit XML report to read failing tests from, can be repeated",
 			},
 			cli.StringFlag{
-				Name:  "defer",
-				Usage: "defer the follow-up by a duration (for example, 1s, 1m, or 2h)",
-				Value: "0s",
+				Name:  "log",
+				Usage: "CI log file to excerpt failures from",
 			},
 			cli.StringFlag{
-				Name:  "output, o",
-				Usage: "output format (raw or json)",
-				Value: string(outputFormatRaw),
+				Name:  "prompt",
+				Usage: "instructions to send along with the failure summary",
 			},
-			cli.BoolFlag{
-				Name:  "skip-missing",
-				Usage: "exit successfully when the pull request is not associated with a workbench job",
+		}, followupFlags()...),
+	}
+}
+
+func (w *Workbenches) hooksCommand() cli.Command {
+	return cli.Command{
+		Name:  "hooks",
+		Usage: "manage git hooks that send workbench follow-ups",
+		Subcommands: []cli.Command{
+			{
+				Name:   "install",
+				Usage:  "install a git hook sending a follow-up to the pull request's workbench job after every push",
+				Action: w.handleHooksInstall,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "prompt",
+						Usage: "follow-up prompt sent after a push",
+						Value: defaultHookPrompt,
+					},
+					cli.DurationFlag{
+						Name:  "defer",
+						Usage: "how long to defer the follow-up so it arrives after the push lands",
+						Value: defaultHookDefer,
+					},
+					cli.BoolFlag{
+						Name:  "force",
+						Usage: "replace an existing pre-push hook not installed by plural",
+					},
+				},
 			},
 		},
 	}
 }
 
+func followupFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "url",
+			Usage: "pull request URL; bypasses commit inference",
+		},
+		cli.StringFlag{
+			Name:  "commit",
+			Usage: "commit or ref whose subject identifies the pull request (defaults to HEAD)",
+		},
+		cli.StringFlag{
+			Name:  "base-url",
+			Usage: "repository web URL used to construct the pull request URL",
+		},
+		cli.StringFlag{
+			Name:  "provider",
+			Usage: "source control provider (auto, github, gitlab, bitbucket, bitbucket-server, azure-devops, or gitea)",
+			Value: string(ProviderAuto),
+		},
+		cli.BoolFlag{
+			Name:  "lookup-commit",
+			Usage: "look the pull request up by commit through the provider API when the commit subject does not reference one",
+		},
+		cli.StringFlag{
+			Name:  "defer",
+			Usage: "defer the follow-up by a duration (for example, 1s, 1m, or 2h)",
+			Value: "0s",
+		},
+		cli.StringFlag{
+			Name:  "output, o",
+			Usage: "output format (raw or json)",
+			Value: string(outputFormatRaw),
+		},
+		cli.BoolFlag{
+			Name:  "skip-missing",
+			Usage: "exit successfully when the pull request is not associated with a workbench job",
+		},
+	}
+}
+
+func (w *Workbenches) handleList(ctx *cli.Context) error {
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	workbenches, err := w.ConsoleClient.ListWorkbenches(int64(ctx.Int("limit")))
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"ID", "Name", "Description"}
+	return utils.PrintTable(workbenches, headers, func(workbench *consoleclient.WorkbenchTinyFragment) ([]string, error) {
+		return []string{workbench.ID, workbench.Name, lo.FromPtr(workbench.Description)}, nil
+	})
+}
+
+func (w *Workbenches) handleJobs(ctx *cli.Context) error {
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	jobs, err := NewJobService(w.ConsoleClient, os.Stdout).ListJobs(ctx.Args().First(), JobFilter{
+		Status: ctx.String("status"),
+		Limit:  int64(ctx.Int("limit")),
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"ID", "Status", "Created", "Prompt"}
+	return utils.PrintTable(jobs, headers, func(job *consoleclient.WorkbenchJobTinyFragment) ([]string, error) {
+		return []string{job.ID, strings.ToLower(string(job.Status)), lo.FromPtr(job.InsertedAt), utils.Summarize(lo.FromPtr(job.Prompt), 60)}, nil
+	})
+}
+
+func (w *Workbenches) handleLogs(ctx *cli.Context) error {
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	return NewJobService(w.ConsoleClient, os.Stdout).Logs(ctx.Args().First(), LogOptions{
+		Follow:   ctx.Bool("follow"),
+		Interval: ctx.Duration("interval"),
+	})
+}
+
+func (w *Workbenches) handlePrompt(ctx *cli.Context) error {
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	jobID := ctx.Args().First()
+	if _, err := NewJobService(w.ConsoleClient, os.Stdout).Prompt(jobID, ctx.String("prompt")); err != nil {
+		return err
+	}
+
+	utils.Success("Sent prompt to workbench job %s\n", jobID)
+	return nil
+}
+
+func (w *Workbenches) handleQueueList(ctx *cli.Context) error {
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	prompts, err := NewJobService(w.ConsoleClient, os.Stdout).Queue(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"ID", "Dequeues At", "Prompt"}
+	return utils.PrintTable(prompts, headers, func(prompt *consoleclient.QueuedPromptFragment) ([]string, error) {
+		return []string{prompt.ID, lo.FromPtr(prompt.DequeableAt), utils.Summarize(prompt.Prompt, 60)}, nil
+	})
+}
+
+func (w *Workbenches) handleQueueCancel(ctx *cli.Context) error {
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	id := ctx.Args().First()
+	if err := w.ConsoleClient.DeleteQueuedPrompt(id); err != nil {
+		return err
+	}
+
+	utils.Success("Cancelled queued prompt %s\n", id)
+	return nil
+}
+
 func (w *Workbenches) handlePRFollowup(ctx *cli.Context) error {
 	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
 		return err
 	}
 
+	return w.sendFollowup(ctx, ctx.String("prompt"))
+}
+
+func (w *Workbenches) handleCI(ctx *cli.Context) error {
+	report, err := readCIReport(ctx.StringSlice("junit"), ctx.String("log"))
+	if err != nil {
+		return err
+	}
+	if report.Empty() {
+		utils.Success("No CI failures found; skipping\n")
+		return nil
+	}
+
+	if err := w.InitConsoleClient(w.consoleToken, w.consoleURL); err != nil {
+		return err
+	}
+
+	return w.sendFollowup(ctx, BuildCIFollowupPrompt(report, ctx.String("prompt")))
+}
+
+func (w *Workbenches) handleHooksInstall(ctx *cli.Context) error {
+	hooksDir, err := gitutils.GitRaw("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return fmt.Errorf("could not locate the git hooks directory: %w", err)
+	}
+
+	path, err := InstallHook(strings.TrimSpace(hooksDir), HookOptions{
+		Prompt:  ctx.String("prompt"),
+		DeferBy: ctx.Duration("defer"),
+		Force:   ctx.Bool("force"),
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.Success("Installed workbench follow-up hook at %s\n", path)
+	return nil
+}
+
+func (w *Workbenches) sendFollowup(ctx *cli.Context, prompt string) error {
 	deferBy, err := time.ParseDuration(ctx.String("defer"))
 	if err != nil {
 		return fmt.Errorf("invalid defer duration: %w", err)
@@ -132,16 +429,18 @@ func (w *Workbenches) handlePRFollowup(ctx *cli.Context) error {
 		return err
 	}
 
-	service := NewPRFollowupService(w.ConsoleClient, NewPullRequestResolver(nil))
+	resolver := NewPullRequestResolver(nil).WithHosts(config.Read().SCMHosts)
+	service := NewPRFollowupService(w.ConsoleClient, resolver)
 	result, err := service.Create(PRFollowupOptions{
-		Prompt:      ctx.String("prompt"),
+		Prompt:      prompt,
 		DeferBy:     deferBy,
 		SkipMissing: ctx.Bool("skip-missing"),
 		PullRequest: PullRequestOptions{
-			URL:      ctx.String("url"),
-			Commit:   ctx.String("commit"),
-			BaseURL:  ctx.String("base-url"),
-			Provider: ctx.String("provider"),
+			URL:            ctx.String("url"),
+			Commit:         ctx.String("commit"),
+			BaseURL:        ctx.String("base-url"),
+			Provider:       ctx.String("provider"),
+			LookupByCommit: ctx.Bool("lookup-commit"),
 		},
 	})
 	if err != nil {
@@ -151,6 +450,39 @@ func (w *Workbenches) handlePRFollowup(ctx *cli.Context) error {
 	return w.writePRFollowupResult(output, result)
 }
 
+func readCIReport(junitPaths []string, logPath string) (CIReport, error) {
+	if len(junitPaths) == 0 && logPath == "" {
+		return CIReport{}, fmt.Errorf("one of --junit or --log is required")
+	}
+
+	report := CIReport{}
+	for _, path := range junitPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			return report, err
+		}
+		failures, err := ParseJUnitReport(file)
+		file.Close()
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", path, err)
+		}
+		report.Failures = append(report.Failures, failures...)
+	}
+
+	if logPath != "" {
+		file, err := os.Open(logPath)
+		if err != nil {
+			return report, err
+		}
+		defer file.Close()
+		if report.LogLines, err = ParseCILog(file); err != nil {
+			return report, fmt.Errorf("%s: %w", logPath, err)
+		}
+	}
+
+	return report, nil
+}
+
 func (w *Workbenches) writePRFollowupResult(output outputFormat, result PRFollowupResult) error {
 	switch output {
 	case outputFormatRaw:
@@ -23,22 +23,24 @@ func TestCommandShape(t *testing.T) {
 
 	assert.Equal(t, "workbenches", command.Name)
 	assert.Contains(t, command.Aliases, "wb")
-	require.Len(t, command.Subcommands, 1)
+	require.Len(t, command.Subcommands, 8)
 	assert.Equal(t, "pr-followup", command.Subcommands[0].Name)
+	assert.Equal(t, []string{"pr-followup", "list", "jobs", "logs", "prompt", "queue", "ci", "hooks"}, commandNames(command.Subcommands))
 	assert.Equal(t, map[string]bool{
 		"console-url": true,
 		"token":       true,
 	}, flagNames(command.Flags))
 	assert.Equal(t, map[string]bool{
-		"base-url":     true,
-		"commit":       true,
-		"defer":        true,
-		"o":            true,
-		"output":       true,
-		"prompt":       true,
-		"provider":     true,
-		"skip-missing": true,
-		"url":          true,
+		"base-url":      true,
+		"commit":        true,
+		"defer":         true,
+		"lookup-commit": true,
+		"o":             true,
+		"output":        true,
+		"prompt":        true,
+		"provider":      true,
+		"skip-missing":  true,
+		"url":           true,
 	}, flagNames(command.Subcommands[0].Flags))
 }
 
@@ -145,6 +147,15 @@ func flagNames(flags []cli.Flag) map[string]bool {
 	return names
 }
 
+func commandNames(commands []cli.Command) []string {
+	names := make([]string, 0, len(commands))
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+
+	return names
+}
+
 func prFollowupContext(t *testing.T, args ...string) *cli.Context {
 	t.Helper()
 
@@ -154,6 +165,7 @@ func prFollowupContext(t *testing.T, args ...string) *cli.Context {
 	flags.String("base-url", "", "")
 	flags.String("prompt", "", "")
 	flags.String("provider", string(ProviderAuto), "")
+	flags.Bool("lookup-commit", false, "")
 	flags.String("defer", "0s", "")
 	flags.String("output", "raw", "")
 	flags.String("o", "raw", "")
@@ -212,6 +212,93 @@ func (in TarGzipArchiveReader) subtreeRel(name, archivePath string) (string, boo
 	return strings.TrimPrefix(name, archivePath+"/"), strings.HasPrefix(name, archivePath+"/")
 }
 
+// TarGzipArchiveWriter writes session archives in the format read by
+// TarGzipArchiveReader.
+type TarGzipArchiveWriter struct{}
+
+// Write creates the archive at path with manifest.json followed by every
+// regular file under srcDir, stored relative to srcDir.
+func (in TarGzipArchiveWriter) Write(path string, manifest *SessionManifest, srcDir string) error {
+	if manifest == nil {
+		return fmt.Errorf("session manifest is required")
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	partialPath := path + ".partial"
+	file, err := os.OpenFile(partialPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("create session archive: %w", err)
+	}
+	writeErr := in.write(file, manifestData, srcDir)
+	closeErr := file.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		_ = os.Remove(partialPath)
+		return writeErr
+	}
+	return os.Rename(partialPath, path)
+}
+
+func (in TarGzipArchiveWriter) write(w io.Writer, manifestData []byte, srcDir string) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0644, Size: int64(len(manifestData)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return err
+	}
+
+	err := filepath.WalkDir(srcDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		switch {
+		case info.IsDir():
+			header.Name += "/"
+			return tw.WriteHeader(header)
+		case info.Mode().IsRegular():
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			_, err = io.Copy(tw, file)
+			return err
+		default:
+			return fmt.Errorf("unsupported session file %q", path)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("write session archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
 type foundManifestError struct {
 	manifest *SessionManifest
 }
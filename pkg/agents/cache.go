@@ -0,0 +1,274 @@
+package agents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	console "github.com/pluralsh/console/go/client"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
+)
+
+// cacheMetadataSuffix names the sidecar file recording where an archive, or a
+// partial download of one, came from.
+const cacheMetadataSuffix = ".meta.json"
+
+// CacheMetadata records the origin and checksum of a cached session archive.
+type CacheMetadata struct {
+	// RunID is the console agent run the archive was uploaded by.
+	RunID string `json:"runId,omitempty"`
+	// Provider is the runtime directory the archive is cached under.
+	Provider console.AgentRuntimeType `json:"provider,omitempty"`
+	// ETag is the validator returned by the upload storage, if any.
+	ETag string `json:"etag,omitempty"`
+	// LastModified is the Last-Modified header returned by the upload storage.
+	LastModified string `json:"lastModified,omitempty"`
+	// Size is the archive size in bytes.
+	Size int64 `json:"size"`
+	// SHA256 is the hex encoded checksum of the archive.
+	SHA256 string `json:"sha256"`
+	// DownloadedAt is when the archive finished downloading.
+	DownloadedAt time.Time `json:"downloadedAt"`
+}
+
+// rangeValidator returns the validator used to resume a partial download,
+// weak etags are not allowed in If-Range so Last-Modified is used instead.
+func (m *CacheMetadata) rangeValidator() string {
+	if m == nil {
+		return ""
+	}
+	if m.ETag != "" && !strings.HasPrefix(m.ETag, "W/") {
+		return m.ETag
+	}
+	return m.LastModified
+}
+
+func metadataPath(path string) string {
+	return path + cacheMetadataSuffix
+}
+
+func readCacheMetadata(path string) (*CacheMetadata, error) {
+	data, err := os.ReadFile(metadataPath(path))
+	if err != nil {
+		return nil, err
+	}
+	var metadata CacheMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("decode cache metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+func writeCacheMetadata(path string, metadata *CacheMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metadataPath(path), data, 0644)
+}
+
+func checksumFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifyArchive checks the archive at path against its recorded size and checksum.
+func verifyArchive(path string, metadata *CacheMetadata) error {
+	if metadata == nil || metadata.SHA256 == "" {
+		return fmt.Errorf("no checksum recorded for %s", path)
+	}
+	size, sum, err := checksumFile(path)
+	if err != nil {
+		return err
+	}
+	if size != metadata.Size || sum != metadata.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s, expected %s (%d bytes) but found %s (%d bytes)", path, metadata.SHA256, metadata.Size, sum, size)
+	}
+	return nil
+}
+
+// CacheEntry is the cached session data of a single agent run.
+type CacheEntry struct {
+	// RunID is the agent run the entry belongs to.
+	RunID string
+	// Provider is the runtime directory the entry is stored under.
+	Provider string
+	// Dir is the work directory holding the archive and extracted restore data.
+	Dir string
+	// Size is the total size of Dir in bytes.
+	Size int64
+	// LastUsed is the most recent modification time under Dir.
+	LastUsed time.Time
+	// Partial is set when only an interrupted download is cached.
+	Partial bool
+	// Metadata is the recorded origin of the archive, nil for older downloads.
+	Metadata *CacheMetadata
+}
+
+// ArchivePath returns the path of the cached archive.
+func (e *CacheEntry) ArchivePath() string {
+	return filepath.Join(e.Dir, SessionTarName)
+}
+
+// Verify checks the cached archive against its recorded checksum.
+func (e *CacheEntry) Verify() error {
+	if e.Partial {
+		return fmt.Errorf("download of run %s was interrupted", e.RunID)
+	}
+	return verifyArchive(e.ArchivePath(), e.Metadata)
+}
+
+// PruneOptions select the cache entries removed by Prune.
+type PruneOptions struct {
+	// MaxAge evicts entries unused for longer than this, zero disables it.
+	MaxAge time.Duration
+	// MaxSize evicts the least recently used entries until the cache fits, zero disables it.
+	MaxSize int64
+	// All evicts every entry.
+	All bool
+	// DryRun reports the entries that would be evicted without removing them.
+	DryRun bool
+}
+
+// SessionCache manages the session archives downloaded by HTTPArchiveStore.
+type SessionCache struct {
+	// root is the directory holding per-provider and per-run work directories.
+	root string
+}
+
+// NewSessionCache returns the cache stored in the Plural home directory.
+func NewSessionCache() (*SessionCache, error) {
+	root, err := config.PluralDir("ai", "agents", "sessions")
+	if err != nil {
+		return nil, err
+	}
+	return &SessionCache{root: root}, nil
+}
+
+// List returns every cached run, most recently used first.
+func (c *SessionCache) List() ([]*CacheEntry, error) {
+	providers, err := os.ReadDir(c.root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*CacheEntry, 0)
+	for _, provider := range providers {
+		if !provider.IsDir() {
+			continue
+		}
+		runs, err := os.ReadDir(filepath.Join(c.root, provider.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs {
+			if !run.IsDir() {
+				continue
+			}
+			entry, err := c.entry(provider.Name(), run.Name())
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// Prune removes the entries selected by opts and returns them.
+func (c *SessionCache) Prune(opts PruneOptions) ([]*CacheEntry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	// walk from least to most recently used so size based eviction keeps recent sessions
+	pruned := make([]*CacheEntry, 0)
+	now := time.Now()
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		expired := opts.MaxAge > 0 && now.Sub(entry.LastUsed) > opts.MaxAge
+		oversized := opts.MaxSize > 0 && total > opts.MaxSize
+		if !opts.All && !expired && !oversized {
+			continue
+		}
+		if !opts.DryRun {
+			if err := c.Remove(entry); err != nil {
+				return pruned, err
+			}
+		}
+		total -= entry.Size
+		pruned = append(pruned, entry)
+	}
+	return pruned, nil
+}
+
+// Remove deletes a cached entry.
+func (c *SessionCache) Remove(entry *CacheEntry) error {
+	return os.RemoveAll(entry.Dir)
+}
+
+func (c *SessionCache) entry(provider, runID string) (*CacheEntry, error) {
+	entry := &CacheEntry{
+		RunID:    runID,
+		Provider: provider,
+		Dir:      filepath.Join(c.root, provider, runID),
+	}
+	err := filepath.WalkDir(entry.Dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			entry.Size += info.Size()
+		}
+		if info.ModTime().After(entry.LastUsed) {
+			entry.LastUsed = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(entry.ArchivePath()); err != nil {
+		entry.Partial = true
+		return entry, nil
+	}
+	entry.Metadata, _ = readCacheMetadata(entry.ArchivePath())
+	return entry, nil
+}
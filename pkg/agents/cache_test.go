@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionCachePruneEvictsOldAndOversizedEntries(t *testing.T) {
+	cache := &SessionCache{root: t.TempDir()}
+	now := time.Now()
+	writeCacheEntry(t, cache, "CODEX", "old", strings.Repeat("a", 10), now.Add(-48*time.Hour))
+	writeCacheEntry(t, cache, "CLAUDE", "middle", strings.Repeat("b", 10), now.Add(-2*time.Hour))
+	writeCacheEntry(t, cache, "CLAUDE", "recent", strings.Repeat("c", 10), now.Add(-time.Hour))
+
+	entries, err := cache.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(entries) != 3 || entries[0].RunID != "recent" || entries[2].RunID != "old" {
+		t.Fatalf("expected entries ordered by last use, got %v", runIDs(entries))
+	}
+
+	pruned, err := cache.Prune(PruneOptions{MaxAge: 24 * time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+	if ids := runIDs(pruned); len(ids) != 1 || ids[0] != "old" {
+		t.Fatalf("expected only the old entry to expire, got %v", ids)
+	}
+	if _, err := os.Stat(pruned[0].Dir); err != nil {
+		t.Fatalf("expected dry run to keep the entry: %v", err)
+	}
+
+	pruned, err = cache.Prune(PruneOptions{MaxSize: entries[0].Size})
+	if err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+	if ids := runIDs(pruned); len(ids) != 2 || ids[0] != "old" || ids[1] != "middle" {
+		t.Fatalf("expected the least recently used entries to be evicted, got %v", ids)
+	}
+	remaining, err := cache.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := runIDs(remaining); len(ids) != 1 || ids[0] != "recent" {
+		t.Fatalf("expected only the recent entry to remain, got %v", ids)
+	}
+}
+
+func TestCacheEntryVerifyDetectsCorruption(t *testing.T) {
+	cache := &SessionCache{root: t.TempDir()}
+	dir := writeCacheEntry(t, cache, "CODEX", "run-1", "archive", time.Now())
+	entries, err := cache.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entries[0].Verify(); err != nil {
+		t.Fatalf("expected intact archive to verify: %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, SessionTarName), "tampered")
+	if err := entries[0].Verify(); err == nil {
+		t.Fatalf("expected tampered archive to fail verification")
+	}
+}
+
+func writeCacheEntry(t *testing.T, cache *SessionCache, provider, runID, content string, modTime time.Time) string {
+	t.Helper()
+	dir := filepath.Join(cache.root, provider, runID)
+	mkdir(t, dir)
+	path := filepath.Join(dir, SessionTarName)
+	writeFile(t, path, content)
+	size, sum, err := checksumFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCacheMetadata(path, &CacheMetadata{RunID: runID, Size: size, SHA256: sum}); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{dir, path, metadataPath(path)} {
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runIDs(entries []*CacheEntry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.RunID)
+	}
+	return ids
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	console "github.com/pluralsh/console/go/client"
 
@@ -30,7 +32,14 @@ type ArchiveStore interface {
 	Finalize(download *DownloadedArchive, runID string, provider console.AgentRuntimeType) (*DownloadedArchive, error)
 }
 
+// downloadAttempts bounds how often an interrupted download is resumed.
+const downloadAttempts = 3
+
 // HTTPArchiveStore downloads session archives over HTTP into the Plural cache.
+//
+// Completed downloads are recorded with their checksum and HTTP validators, so
+// unchanged uploads are reused after verification instead of downloaded again,
+// and interrupted downloads resume with HTTP range requests.
 type HTTPArchiveStore struct {
 	// client performs archive download requests.
 	client *http.Client
@@ -58,13 +67,31 @@ func (s *HTTPArchiveStore) Download(ctx context.Context, url, runID string, prov
 
 	finalPath := filepath.Join(workDir, SessionTarName)
 	partialPath := finalPath + ".partial"
-	if err := s.download(ctx, url, partialPath); err != nil {
+	cached := s.verifiedCache(finalPath)
+	if cached != nil {
+		// a conditional request is only made for a fresh download, never a resumed one
 		_ = os.Remove(partialPath)
+		_ = os.Remove(metadataPath(partialPath))
+	}
+
+	metadata, notModified, err := s.download(ctx, url, partialPath, cached)
+	if err != nil {
 		return nil, err
 	}
+	if notModified {
+		// mark the archive as recently used so cache eviction keeps it
+		now := time.Now()
+		_ = os.Chtimes(finalPath, now, now)
+		return &DownloadedArchive{Path: finalPath, WorkDir: workDir}, nil
+	}
 	if err := os.Rename(partialPath, finalPath); err != nil {
 		return nil, fmt.Errorf("store session archive: %w", err)
 	}
+	metadata.RunID = runID
+	metadata.Provider = provider
+	if err := writeCacheMetadata(finalPath, metadata); err != nil {
+		return nil, err
+	}
 	return &DownloadedArchive{Path: finalPath, WorkDir: workDir}, nil
 }
 
@@ -89,32 +116,132 @@ func (s *HTTPArchiveStore) Finalize(download *DownloadedArchive, runID string, p
 		}
 		_ = os.Remove(download.Path)
 	}
+	if metadata, err := readCacheMetadata(download.Path); err == nil {
+		metadata.Provider = provider
+		if err := writeCacheMetadata(finalPath, metadata); err != nil {
+			return nil, err
+		}
+		_ = os.Remove(metadataPath(download.Path))
+	}
 	return &DownloadedArchive{Path: finalPath, WorkDir: finalDir}, nil
 }
 
-func (s *HTTPArchiveStore) download(ctx context.Context, url, path string) error {
+// verifiedCache returns the metadata of a previously downloaded archive at path
+// if its checksum still matches, discarding archives that fail verification.
+func (s *HTTPArchiveStore) verifiedCache(path string) *CacheMetadata {
+	metadata, err := readCacheMetadata(path)
+	if err != nil {
+		return nil
+	}
+	if err := verifyArchive(path, metadata); err != nil {
+		_ = os.Remove(path)
+		_ = os.Remove(metadataPath(path))
+		return nil
+	}
+	if metadata.ETag == "" && metadata.LastModified == "" {
+		return nil
+	}
+	return metadata
+}
+
+// download fetches url into path, retrying interrupted transfers from where
+// they stopped. When cached is set the request is conditional and notModified
+// reports that the cached archive is still current.
+func (s *HTTPArchiveStore) download(ctx context.Context, url, path string, cached *CacheMetadata) (*CacheMetadata, bool, error) {
+	var err error
+	for attempt := 0; attempt < downloadAttempts; attempt++ {
+		var metadata *CacheMetadata
+		var notModified, retry bool
+		metadata, notModified, retry, err = s.fetch(ctx, url, path, cached)
+		if err == nil {
+			return metadata, notModified, nil
+		}
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, false, err
+}
+
+func (s *HTTPArchiveStore) fetch(ctx context.Context, url, path string, cached *CacheMetadata) (*CacheMetadata, bool, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return err
+		return nil, false, false, err
+	}
+
+	var offset int64
+	partial, _ := readCacheMetadata(path)
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 && partial.rangeValidator() != "" {
+		offset = info.Size()
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", partial.rangeValidator())
+	} else if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		} else {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
 	}
+
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("download session archive: %w", err)
+		return nil, false, true, fmt.Errorf("download session archive: %w", err)
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download session archive: unexpected status %d", resp.StatusCode)
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached, true, false, nil
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			s.discard(path)
+			return nil, false, true, fmt.Errorf("download session archive: unexpected content range %q", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// either a fresh download, or the upload changed since the partial download started
+		flags |= os.O_TRUNC
+		partial = &CacheMetadata{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+		if err := writeCacheMetadata(path, partial); err != nil {
+			return nil, false, false, err
+		}
+	default:
+		s.discard(path)
+		return nil, false, false, fmt.Errorf("download session archive: unexpected status %d", resp.StatusCode)
 	}
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+
+	file, err := os.OpenFile(path, flags, 0644)
 	if err != nil {
-		return err
+		return nil, false, false, err
 	}
 	_, copyErr := io.Copy(file, resp.Body)
 	closeErr := file.Close()
 	if copyErr != nil {
-		return copyErr
+		// keep what was written so the next attempt can resume from it
+		return nil, false, true, fmt.Errorf("download session archive: %w", copyErr)
+	}
+	if closeErr != nil {
+		return nil, false, false, closeErr
 	}
-	return closeErr
+
+	size, sum, err := checksumFile(path)
+	if err != nil {
+		return nil, false, false, err
+	}
+	_ = os.Remove(metadataPath(path))
+	return &CacheMetadata{
+		ETag:         partial.ETag,
+		LastModified: partial.LastModified,
+		Size:         size,
+		SHA256:       sum,
+		DownloadedAt: time.Now().UTC(),
+	}, false, false, nil
+}
+
+func (s *HTTPArchiveStore) discard(path string) {
+	_ = os.Remove(path)
+	_ = os.Remove(metadataPath(path))
 }
 
 func (s *HTTPArchiveStore) sessionWorkDir(provider console.AgentRuntimeType, runID string) (string, error) {
@@ -40,6 +40,98 @@ func TestHTTPArchiveStoreDownloadStoresUnderPluralHome(t *testing.T) {
 	}
 }
 
+func TestHTTPArchiveStoreDownloadReusesUnchangedArchive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var requests []*http.Request
+	store := NewHTTPArchiveStore(&http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			return &http.Response{StatusCode: http.StatusNotModified, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": []string{`"v1"`}},
+			Body:       io.NopCloser(bytes.NewBufferString("archive")),
+		}, nil
+	})})
+
+	first, err := store.Download(context.Background(), "https://example.com/session.tar.gz?sig=1", "run-1", client.AgentRuntimeTypeCodex)
+	if err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+	second, err := store.Download(context.Background(), "https://example.com/session.tar.gz?sig=2", "run-1", client.AgentRuntimeTypeCodex)
+	if err != nil {
+		t.Fatalf("second Download returned error: %v", err)
+	}
+	if len(requests) != 2 || requests[1].Header.Get("If-None-Match") != `"v1"` {
+		t.Fatalf("expected a conditional request for the cached archive")
+	}
+	if first.Path != second.Path {
+		t.Fatalf("expected cached archive to be reused, got %q and %q", first.Path, second.Path)
+	}
+	assertFileContent(t, second.Path, "archive")
+
+	// a corrupted archive must be downloaded again rather than reused
+	writeFile(t, second.Path, "corrupt")
+	requests = nil
+	if _, err := store.Download(context.Background(), "https://example.com/session.tar.gz?sig=3", "run-1", client.AgentRuntimeTypeCodex); err != nil {
+		t.Fatalf("third Download returned error: %v", err)
+	}
+	if len(requests) != 1 || requests[0].Header.Get("If-None-Match") != "" {
+		t.Fatalf("expected an unconditional request after verification failed")
+	}
+	assertFileContent(t, second.Path, "archive")
+}
+
+func TestHTTPArchiveStoreDownloadResumesInterruptedTransfer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	content := "0123456789abcdef"
+	var ranges []string
+	store := NewHTTPArchiveStore(&http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		ranges = append(ranges, req.Header.Get("Range"))
+		if rng := req.Header.Get("Range"); rng != "" {
+			if req.Header.Get("If-Range") != `"v1"` {
+				t.Fatalf("expected If-Range to carry the etag, got %q", req.Header.Get("If-Range"))
+			}
+			return &http.Response{
+				StatusCode: http.StatusPartialContent,
+				Header:     http.Header{"Content-Range": []string{"bytes 6-15/16"}},
+				Body:       io.NopCloser(bytes.NewBufferString(content[6:])),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": []string{`"v1"`}},
+			Body:       io.NopCloser(io.MultiReader(bytes.NewBufferString(content[:6]), failingReader{})),
+		}, nil
+	})})
+
+	download, err := store.Download(context.Background(), "https://example.com/session.tar.gz", "run-1", client.AgentRuntimeTypeCodex)
+	if err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=6-" {
+		t.Fatalf("expected the second request to resume from byte 6, got %v", ranges)
+	}
+	assertFileContent(t, download.Path, content)
+
+	metadata, err := readCacheMetadata(download.Path)
+	if err != nil {
+		t.Fatalf("read cache metadata: %v", err)
+	}
+	if err := verifyArchive(download.Path, metadata); err != nil {
+		t.Fatalf("expected resumed archive to verify: %v", err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
 type roundTripper func(*http.Request) (*http.Response, error)
 
 func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -0,0 +1,138 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	console "github.com/pluralsh/console/go/client"
+)
+
+// launchBranchPrefix namespaces the branches local changes are pushed to, so
+// they are easy to find and clean up.
+const launchBranchPrefix = "plural/agent-runs/"
+
+// LaunchContext describes the local checkout a new agent run is started from.
+type LaunchContext struct {
+	// Repository is the git remote URL of the local checkout.
+	Repository string
+	// Branch is the remote branch the run should start from.
+	Branch string
+	// Snapshot is the commit holding local changes missing from the remote
+	// branch, if requested. It is pushed to Branch so the run starts from it.
+	Snapshot string
+}
+
+// ParseRuntime normalizes a user supplied runtime name onto the console enum.
+func ParseRuntime(name string) (console.AgentRuntimeType, error) {
+	runtime := console.AgentRuntimeType(strings.ToUpper(strings.TrimSpace(name)))
+	if !runtime.IsValid() {
+		return "", fmt.Errorf("unsupported agent runtime %q, must be one of claude, codex, gemini or opencode", name)
+	}
+	return runtime, nil
+}
+
+// LaunchContext reads the origin and pushed branch of the checkout at repoPath.
+// When includeChanges is set, everything in the working tree that is not on the
+// remote branch is committed to a snapshot: unpushed commits, staged and
+// unstaged edits, and untracked files. The snapshot is pushed to its own
+// branch, which the run starts from instead of the remote branch, so the
+// changes never have to travel through the prompt.
+func (p *GitRepository) LaunchContext(repoPath string, includeChanges bool) (*LaunchContext, error) {
+	if _, err := p.git(repoPath, "rev-parse", "--show-toplevel"); err != nil {
+		return nil, fmt.Errorf("%q is not a git clone: %w", repoPath, err)
+	}
+	remote, err := p.git(repoPath, "ls-remote", "--get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("could not read git origin for %q: %w", repoPath, err)
+	}
+	branch, err := p.currentBranch(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine current branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return nil, fmt.Errorf("cannot start an agent run from a detached HEAD, check out a branch first")
+	}
+
+	upstream, err := p.upstreamBranch(repoPath, branch)
+	if err != nil {
+		return nil, err
+	}
+	launch := &LaunchContext{
+		Repository: remote,
+		Branch:     strings.TrimPrefix(upstream, "origin/"),
+	}
+	if !includeChanges {
+		return launch, nil
+	}
+
+	if launch.Snapshot, err = p.snapshot(repoPath, upstream); err != nil {
+		return nil, err
+	}
+	if launch.Snapshot == "" {
+		return launch, nil
+	}
+
+	launch.Branch = launchBranchPrefix + launch.Snapshot[:12]
+	if _, err := p.git(repoPath, "push", "origin", launch.Snapshot+":refs/heads/"+launch.Branch); err != nil {
+		return nil, fmt.Errorf("push local changes to %s: %w", launch.Branch, err)
+	}
+	return launch, nil
+}
+
+// upstreamBranch returns the remote tracking branch of branch, falling back to
+// a branch of the same name on origin.
+func (p *GitRepository) upstreamBranch(repoPath, branch string) (string, error) {
+	if upstream, err := p.git(repoPath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}"); err == nil && strings.HasPrefix(upstream, "origin/") {
+		return upstream, nil
+	}
+	if _, err := p.git(repoPath, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+branch); err == nil {
+		return "origin/" + branch, nil
+	}
+	return "", fmt.Errorf("branch %s has not been pushed to origin, push it before starting an agent run", branch)
+}
+
+// snapshot commits the working tree on top of HEAD using a throwaway index, so
+// untracked files are included without touching the user's staging area or
+// branch. It returns an empty string if there is nothing missing from upstream.
+func (p *GitRepository) snapshot(repoPath, upstream string) (string, error) {
+	dir, err := os.MkdirTemp("", "plural-agent-launch")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := Executable(repoPath, "GIT_INDEX_FILE="+filepath.Join(dir, "index"))
+	ctx := context.Background()
+	if _, err := cmd.Output(ctx, "git", "read-tree", "HEAD"); err != nil {
+		return "", fmt.Errorf("read HEAD into temporary index: %w", err)
+	}
+	if _, err := cmd.Output(ctx, "git", "add", "--all"); err != nil {
+		return "", fmt.Errorf("stage local changes in temporary index: %w", err)
+	}
+	tree, err := cmd.Output(ctx, "git", "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("write local changes: %w", err)
+	}
+
+	if upstreamTree, err := p.git(repoPath, "rev-parse", upstream+"^{tree}"); err == nil && upstreamTree == tree {
+		return "", nil
+	}
+
+	head, err := p.git(repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if headTree, err := p.git(repoPath, "rev-parse", "HEAD^{tree}"); err == nil && headTree == tree {
+		// only unpushed commits, which can be pushed as they are
+		return head, nil
+	}
+
+	commit, err := cmd.Output(ctx, "git", "commit-tree", tree, "-p", head, "-m", "Local changes for agent run")
+	if err != nil {
+		return "", fmt.Errorf("commit local changes: %w", err)
+	}
+	return commit, nil
+}
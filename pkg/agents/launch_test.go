@@ -0,0 +1,125 @@
+package agents
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitRepositoryLaunchContextIncludesUnpushedChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	root := t.TempDir()
+	source := filepath.Join(root, "source")
+	origin := filepath.Join(root, "origin.git")
+	clone := filepath.Join(root, "clone")
+	verify := filepath.Join(root, "verify")
+
+	mkdir(t, source)
+	git(t, source, "init")
+	git(t, source, "config", "user.email", "test@example.com")
+	git(t, source, "config", "user.name", "Test User")
+	git(t, source, "config", "commit.gpgsign", "false")
+	git(t, source, "checkout", "-b", "main")
+	writeFile(t, filepath.Join(source, "README.md"), "base\n")
+	git(t, source, "add", "README.md")
+	git(t, source, "commit", "-m", "base")
+	git(t, root, "clone", "--bare", source, origin)
+	git(t, root, "clone", origin, clone)
+	git(t, clone, "config", "user.email", "test@example.com")
+	git(t, clone, "config", "user.name", "Test User")
+	git(t, clone, "config", "commit.gpgsign", "false")
+
+	writeFile(t, filepath.Join(clone, "README.md"), "base\nunpushed\n")
+	git(t, clone, "commit", "-am", "unpushed")
+	writeFile(t, filepath.Join(clone, "README.md"), "base\nunpushed\nuncommitted\n")
+	writeFile(t, filepath.Join(clone, "NOTES.md"), "untracked\n")
+
+	launch, err := NewGitRepository(nil, nil).LaunchContext(clone, true)
+	if err != nil {
+		t.Fatalf("LaunchContext returned error: %v", err)
+	}
+	if !strings.HasPrefix(launch.Branch, launchBranchPrefix) {
+		t.Fatalf("expected launch branch under %s, got %q", launchBranchPrefix, launch.Branch)
+	}
+	if launch.Repository != origin {
+		t.Fatalf("expected launch repository %q, got %q", origin, launch.Repository)
+	}
+
+	status := git(t, clone, "status", "--porcelain")
+	if !strings.Contains(status, "?? NOTES.md") || !strings.Contains(status, " M README.md") {
+		t.Fatalf("expected the user's index to be untouched, got status %q", status)
+	}
+	if branch := strings.TrimSpace(git(t, clone, "rev-parse", "--abbrev-ref", "HEAD")); branch != "main" {
+		t.Fatalf("expected the user to stay on main, got %q", branch)
+	}
+
+	git(t, root, "clone", "-b", launch.Branch, origin, verify)
+	for file, expected := range map[string]string{"README.md": "base\nunpushed\nuncommitted\n", "NOTES.md": "untracked\n"} {
+		content, err := os.ReadFile(filepath.Join(verify, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != expected {
+			t.Fatalf("unexpected content for %s on the launch branch: %q", file, string(content))
+		}
+	}
+}
+
+func TestGitRepositoryLaunchContextWithoutChangesUsesUpstream(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	root := t.TempDir()
+	source := filepath.Join(root, "source")
+	origin := filepath.Join(root, "origin.git")
+	clone := filepath.Join(root, "clone")
+
+	mkdir(t, source)
+	git(t, source, "init")
+	git(t, source, "config", "user.email", "test@example.com")
+	git(t, source, "config", "user.name", "Test User")
+	git(t, source, "config", "commit.gpgsign", "false")
+	git(t, source, "checkout", "-b", "main")
+	writeFile(t, filepath.Join(source, "README.md"), "base\n")
+	git(t, source, "add", "README.md")
+	git(t, source, "commit", "-m", "base")
+	git(t, root, "clone", "--bare", source, origin)
+	git(t, root, "clone", origin, clone)
+
+	launch, err := NewGitRepository(nil, nil).LaunchContext(clone, true)
+	if err != nil {
+		t.Fatalf("LaunchContext returned error: %v", err)
+	}
+	if launch.Branch != "main" || launch.Snapshot != "" {
+		t.Fatalf("expected a clean checkout to start from main, got %+v", launch)
+	}
+}
+
+func TestGitRepositoryLaunchContextRequiresPushedBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := initRepoWithOrigin(t, "https://github.com/pluralsh/plural.git")
+	git(t, repo, "checkout", "-b", "local-only")
+	if _, err := NewGitRepository(nil, nil).LaunchContext(repo, false); err == nil || !strings.Contains(err.Error(), "has not been pushed") {
+		t.Fatalf("expected unpushed branch error, got %v", err)
+	}
+}
+
+func TestParseRuntime(t *testing.T) {
+	for _, name := range []string{"claude", "Codex", " gemini ", "opencode"} {
+		if _, err := ParseRuntime(name); err != nil {
+			t.Fatalf("expected %q to parse, got %v", name, err)
+		}
+	}
+	if _, err := ParseRuntime("aider"); err == nil {
+		t.Fatalf("expected unknown runtime to fail")
+	}
+}
@@ -50,8 +50,9 @@ func (m *SessionManifest) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// runtimes outside the console enum are allowed so restorer plugins can handle them
 	provider := console.AgentRuntimeType(strings.ToUpper(strings.TrimSpace(decoded.Provider)))
-	if provider != "" && !provider.IsValid() {
+	if provider != "" && !provider.IsValid() && !pluginRuntimeName.MatchString(string(provider)) {
 		return fmt.Errorf("%s is not a valid AgentRuntimeType", decoded.Provider)
 	}
 	*m = SessionManifest{
@@ -0,0 +1,169 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	console "github.com/pluralsh/console/go/client"
+)
+
+// PushedSessionTarName is the archive written by Package, kept beside the
+// downloaded archive so a failed upload can be inspected or retried.
+const PushedSessionTarName = "agent-session.pushed.tar.gz"
+
+// PackageOptions are the inputs a provider packager needs to collect local
+// session state for upload.
+type PackageOptions struct {
+	// RepoPath is the local repository checkout the session was resumed in.
+	RepoPath string `json:"repoPath"`
+	// StagingDir is the directory mirroring the archive layout to populate.
+	StagingDir string `json:"stagingDir"`
+	// Manifest is the manifest of the archive the session was restored from.
+	Manifest *SessionManifest `json:"manifest"`
+}
+
+// SessionPackager collects local provider session state into the archive
+// layout its restorer extracts, so pushed archives round-trip through resume.
+type SessionPackager interface {
+	// Provider returns the console runtime type this packager supports.
+	Provider() console.AgentRuntimeType
+	// Package copies session files under opts.StagingDir and returns the
+	// archive path of the subtree holding them.
+	Package(ctx context.Context, opts PackageOptions) (string, error)
+}
+
+// ArchiveUploader uploads a session archive to a console provided URL.
+type ArchiveUploader interface {
+	// Upload sends the archive at path to url.
+	Upload(ctx context.Context, url, path string) error
+}
+
+// HTTPArchiveUploader uploads session archives to presigned URLs with PUT.
+type HTTPArchiveUploader struct {
+	// client performs archive upload requests.
+	client *http.Client
+}
+
+// NewHTTPArchiveUploader returns an archive uploader using the supplied HTTP client.
+func NewHTTPArchiveUploader(client *http.Client) *HTTPArchiveUploader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPArchiveUploader{client: client}
+}
+
+func (u *HTTPArchiveUploader) Upload(ctx context.Context, url, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open session archive: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/gzip")
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload session archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("upload session archive: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Package collects the local provider session the bundle was restored into and
+// writes it to a new archive in the bundle work directory. The manifest keeps
+// the original schema with the branch updated to the one being pushed.
+func (s *SessionService) Package(ctx context.Context, bundle *SessionBundle, repoPath, branch string) (string, error) {
+	if bundle == nil || bundle.Manifest == nil {
+		return "", fmt.Errorf("session bundle is required")
+	}
+	if err := s.repository.ValidateRepository(repoPath, bundle.Manifest); err != nil {
+		return "", err
+	}
+	restorer, err := s.registry.ForProvider(bundle.Manifest.Provider)
+	if err != nil {
+		return "", err
+	}
+	packager, ok := restorer.(SessionPackager)
+	if !ok {
+		return "", fmt.Errorf("pushing %s sessions is not supported", bundle.Manifest.Provider)
+	}
+
+	stagingDir := filepath.Join(bundle.WorkDir, "push")
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	archivePath, err := packager.Package(ctx, PackageOptions{
+		RepoPath:   repoPath,
+		StagingDir: stagingDir,
+		Manifest:   bundle.Manifest,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	manifest := *bundle.Manifest
+	manifest.Branch = branch
+	manifest.Session.ArchivePath = archivePath
+	path := filepath.Join(bundle.WorkDir, PushedSessionTarName)
+	if err := (TarGzipArchiveWriter{}).Write(path, &manifest, stagingDir); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// findSessionFiles returns the regular files under dir accepted by match, a
+// missing directory has no files.
+func (r *baseRestorer) findSessionFiles(dir string, match func(path string) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if match(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// packageFiles copies files, which must live under srcDir, into dstDir keeping
+// their paths relative to srcDir.
+func (r *baseRestorer) packageFiles(srcDir string, files []string, dstDir string) error {
+	for _, file := range files {
+		rel, err := filepath.Rel(srcDir, file)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := r.copyFile(file, filepath.Join(dstDir, rel), info.Mode().Perm(), overwriteExisting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
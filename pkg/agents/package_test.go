@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	console "github.com/pluralsh/console/go/client"
+)
+
+func TestCodexRestorerPackageRoundTripsThroughRestore(t *testing.T) {
+	codexHome := t.TempDir()
+	t.Setenv("CODEX_HOME", codexHome)
+
+	sessionContent := `{"type":"session_meta","payload":{"id":"session-id","timestamp":"2026-06-02T10:00:00Z"}}`
+	local := filepath.Join(codexHome, "sessions", "2026", "06", "02", "rollout-session-id.jsonl")
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, local, sessionContent)
+	writeFile(t, filepath.Join(filepath.Dir(local), "rollout-other.jsonl"), `{"type":"session_meta","payload":{"id":"other"}}`)
+
+	manifest := &SessionManifest{
+		Version:    1,
+		AgentRunID: "run-1",
+		Provider:   console.AgentRuntimeTypeCodex,
+		Repository: "git@github.com:pluralsh/plural.git",
+		Session:    SessionMetadata{ID: "session-id"},
+	}
+	restorer := &CodexRestorer{baseRestorer: baseRestorer{archive: TarGzipArchiveReader{}}}
+	stagingDir := t.TempDir()
+	archivePath, err := restorer.Package(context.Background(), PackageOptions{
+		RepoPath:   filepath.Join(t.TempDir(), "plural"),
+		StagingDir: stagingDir,
+		Manifest:   manifest,
+	})
+	if err != nil {
+		t.Fatalf("Package returned error: %v", err)
+	}
+	if archivePath != "sessions" {
+		t.Fatalf("expected sessions archive path, got %q", archivePath)
+	}
+
+	pushed := *manifest
+	pushed.Branch = "feature"
+	pushed.Session.ArchivePath = archivePath
+	archive := filepath.Join(t.TempDir(), PushedSessionTarName)
+	if err := (TarGzipArchiveWriter{}).Write(archive, &pushed, stagingDir); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	reader := TarGzipArchiveReader{}
+	read, err := reader.ReadManifest(archive)
+	if err != nil {
+		t.Fatalf("ReadManifest returned error: %v", err)
+	}
+	if err := read.Validate(&console.AgentRunMinimalFragment{ID: "run-1"}); err != nil {
+		t.Fatalf("pushed manifest is invalid: %v", err)
+	}
+	if read.Branch != "feature" || read.Provider != console.AgentRuntimeTypeCodex || read.Session.ID != "session-id" {
+		t.Fatalf("unexpected pushed manifest: %#v", read)
+	}
+	if ok, err := reader.Contains(archive, "sessions/2026/06/02/rollout-session-id.jsonl"); err != nil || !ok {
+		t.Fatalf("expected pushed archive to contain the session file, got %v %v", ok, err)
+	}
+	if ok, _ := reader.Contains(archive, "sessions/2026/06/02/rollout-other.jsonl"); ok {
+		t.Fatalf("expected pushed archive to only contain the pushed session")
+	}
+
+	if err := os.RemoveAll(filepath.Join(codexHome, "sessions")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restorer.Prepare(context.Background(), RestoreOptions{
+		RepoPath:    filepath.Join(t.TempDir(), "plural"),
+		ArchivePath: archive,
+		WorkDir:     t.TempDir(),
+		Manifest:    read,
+	}); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	assertFileContent(t, local, sessionContent)
+}
+
+func TestClaudeRestorerPackageRequiresLocalSession(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	restorer := &ClaudeRestorer{baseRestorer: baseRestorer{archive: TarGzipArchiveReader{}}}
+	_, err := restorer.Package(context.Background(), PackageOptions{
+		RepoPath:   filepath.Join(t.TempDir(), "plural"),
+		StagingDir: t.TempDir(),
+		Manifest:   &SessionManifest{Session: SessionMetadata{ID: "missing"}},
+	})
+	if err == nil {
+		t.Fatalf("expected missing claude session to fail")
+	}
+}
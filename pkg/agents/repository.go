@@ -259,6 +259,26 @@ func (p *GitRepository) downloadPatch(ctx context.Context, url, path string) err
 	return closeErr
 }
 
+// Branch returns the branch checked out in repoPath, failing on a detached HEAD.
+func (p *GitRepository) Branch(repoPath string) (string, error) {
+	branch, err := p.currentBranch(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("could not determine current branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return "", fmt.Errorf("the local clone has a detached HEAD, check out a branch first")
+	}
+	return branch, nil
+}
+
+// PushBranch pushes branch to origin, setting it as the upstream.
+func (p *GitRepository) PushBranch(repoPath, branch string) error {
+	if _, err := p.git(repoPath, "push", "--set-upstream", "origin", branch); err != nil {
+		return fmt.Errorf("push branch %q: %w", branch, err)
+	}
+	return nil
+}
+
 func (p *GitRepository) git(repoPath string, args ...string) (string, error) {
 	return Executable(repoPath).Output(context.Background(), "git", args...)
 }
@@ -15,28 +15,28 @@ import (
 // session state from an uploaded archive.
 type RestoreOptions struct {
 	// RepoPath is the selected local repository checkout.
-	RepoPath string
+	RepoPath string `json:"repoPath"`
 	// ArchivePath is the local path to the downloaded session archive.
-	ArchivePath string
+	ArchivePath string `json:"archivePath"`
 	// WorkDir is the local directory used for extracted restore data.
-	WorkDir string
+	WorkDir string `json:"workDir"`
 	// Manifest is the validated session manifest from the archive.
-	Manifest *SessionManifest
+	Manifest *SessionManifest `json:"manifest"`
 	// Interaction prompts before overwriting existing provider session data.
-	Interaction Confirmer
+	Interaction Confirmer `json:"-"`
 	// ConfirmOverwrite overrides overwrite prompting for tests and custom flows.
-	ConfirmOverwrite OverwritePrompt
+	ConfirmOverwrite OverwritePrompt `json:"-"`
 }
 
 // PreparedSession is the local provider session after archive extraction and
 // before invoking the provider's resume command.
 type PreparedSession struct {
 	// RepoPath is the local repository where the provider resume command runs.
-	RepoPath string
+	RepoPath string `json:"repoPath"`
 	// WorkDir is the local directory containing restored provider files.
-	WorkDir string
+	WorkDir string `json:"workDir"`
 	// SessionID is the provider-specific session identifier to resume.
-	SessionID string
+	SessionID string `json:"sessionId"`
 }
 
 // SessionRestorer restores and resumes sessions for one agent runtime provider.
@@ -55,6 +55,37 @@ func (r *ClaudeRestorer) Resume(ctx context.Context, prepared *PreparedSession)
 	return Executable(prepared.RepoPath).Run(ctx, "claude", "--resume", prepared.SessionID)
 }
 
+// Package copies the session transcript, and any subagent state stored beside
+// it, from the local project directory of repoPath.
+func (r *ClaudeRestorer) Package(_ context.Context, opts PackageOptions) (string, error) {
+	sessionID := opts.Manifest.Session.ID
+	if sessionID == "" {
+		return "", fmt.Errorf("claude session id is required to push")
+	}
+	configDir, err := r.configDir()
+	if err != nil {
+		return "", err
+	}
+	projectName := r.toProjectDirName(opts.RepoPath)
+	localProjectDir := filepath.Join(configDir, "projects", projectName)
+	files, err := r.findSessionFiles(localProjectDir, func(path string) bool {
+		rel, err := filepath.Rel(localProjectDir, path)
+		return err == nil && (rel == sessionID+".jsonl" || strings.HasPrefix(rel, sessionID+string(filepath.Separator)))
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("claude session %s not found in %s", sessionID, localProjectDir)
+	}
+
+	archivePath := "projects"
+	if err := r.packageFiles(localProjectDir, files, filepath.Join(opts.StagingDir, archivePath, projectName)); err != nil {
+		return "", fmt.Errorf("package claude session: %w", err)
+	}
+	return archivePath, nil
+}
+
 func (r *ClaudeRestorer) configDir() (string, error) {
 	return r.baseRestorer.configDir("CLAUDE_CONFIG_DIR", ".claude")
 }
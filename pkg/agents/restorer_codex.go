@@ -69,6 +69,41 @@ func (r *CodexRestorer) Resume(ctx context.Context, prepared *PreparedSession) e
 	return r.resume(ctx, prepared, nil, "codex", "resume", prepared.SessionID, "-C", ".")
 }
 
+// Package copies the session rollout file, keeping its dated layout.
+func (r *CodexRestorer) Package(_ context.Context, opts PackageOptions) (string, error) {
+	sessionID := opts.Manifest.Session.ID
+	if sessionID == "" {
+		return "", fmt.Errorf("codex session id is required to push")
+	}
+	codexHome, err := r.configDir()
+	if err != nil {
+		return "", err
+	}
+	sessionsDir := filepath.Join(codexHome, "sessions")
+	files, err := r.findSessionFiles(sessionsDir, func(path string) bool {
+		if filepath.Ext(path) != ".jsonl" {
+			return false
+		}
+		found, err := r.sessionID(path)
+		return err == nil && found == sessionID
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("codex session %s not found in %s", sessionID, sessionsDir)
+	}
+	if len(files) > 1 {
+		return "", fmt.Errorf("found multiple codex session files for session %s", sessionID)
+	}
+
+	archivePath := "sessions"
+	if err := r.packageFiles(sessionsDir, files, filepath.Join(opts.StagingDir, archivePath)); err != nil {
+		return "", fmt.Errorf("package codex session: %w", err)
+	}
+	return archivePath, nil
+}
+
 func (r *CodexRestorer) configDir() (string, error) {
 	return r.baseRestorer.configDir("CODEX_HOME", ".codex")
 }
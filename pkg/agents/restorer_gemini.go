@@ -53,6 +53,35 @@ func (r *GeminiRestorer) Resume(ctx context.Context, session *PreparedSession) e
 	return r.resume(ctx, session, nil, "gemini", "--resume", session.SessionID)
 }
 
+// Package copies the chat files of the session for the repository.
+func (r *GeminiRestorer) Package(_ context.Context, opts PackageOptions) (string, error) {
+	sessionID := opts.Manifest.Session.ID
+	if sessionID == "" {
+		return "", fmt.Errorf("gemini session id is required to push")
+	}
+	geminiHome, err := r.configDir()
+	if err != nil {
+		return "", err
+	}
+	localChatsDir := filepath.Join(geminiHome, "tmp", r.repoDirBaseName(opts.RepoPath), "chats")
+	files, err := r.findSessionFiles(localChatsDir, func(path string) bool {
+		found, err := r.chatSessionID(path)
+		return err == nil && found == sessionID
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("gemini session %s not found in %s", sessionID, localChatsDir)
+	}
+
+	archivePath := "chats"
+	if err := r.packageFiles(localChatsDir, files, filepath.Join(opts.StagingDir, archivePath)); err != nil {
+		return "", fmt.Errorf("package gemini chats: %w", err)
+	}
+	return archivePath, nil
+}
+
 func (r *GeminiRestorer) configDir() (string, error) {
 	return r.baseRestorer.configDir("GEMINI_CLI_HOME", ".gemini")
 }
@@ -2,6 +2,9 @@ package agents
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/exec"
 	"path/filepath"
 
 	console "github.com/pluralsh/console/go/client"
@@ -45,3 +48,30 @@ func (r *OpencodeRestorer) Resume(ctx context.Context, prepared *PreparedSession
 
 	return nil
 }
+
+// Package exports the session with opencode into the file Resume imports.
+func (r *OpencodeRestorer) Package(ctx context.Context, opts PackageOptions) (string, error) {
+	if opts.Manifest.Session.ID == "" {
+		return "", fmt.Errorf("opencode session id is required to push")
+	}
+	archivePath := "opencode"
+	target := filepath.Join(opts.StagingDir, archivePath, "agent-session.json")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// stdout is captured on its own so progress output cannot corrupt the export
+	cmd := exec.CommandContext(ctx, "opencode", "export", opts.Manifest.Session.ID)
+	cmd.Dir = opts.RepoPath
+	cmd.Stdout = file
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("export opencode session %s: %w", opts.Manifest.Session.ID, err)
+	}
+	return archivePath, nil
+}
@@ -0,0 +1,145 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	console "github.com/pluralsh/console/go/client"
+)
+
+const (
+	// RestorerPluginPrefix is the executable name prefix of external restorers,
+	// the runtime name in lower case follows it, eg plural-restorer-aider.
+	RestorerPluginPrefix = "plural-restorer-"
+	// RestorerPluginProtocol is the plugin protocol version passed to plugins in
+	// the PLURAL_RESTORER_PROTOCOL environment variable.
+	RestorerPluginProtocol = "1"
+	// PreparedSessionEnv carries the PreparedSession JSON to the resume command,
+	// whose stdio is attached to the terminal.
+	PreparedSessionEnv = "PLURAL_PREPARED_SESSION"
+)
+
+// pluginRuntimeName restricts runtime names to ones safe to embed in an
+// executable name.
+var pluginRuntimeName = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]*$`)
+
+// PluginRestorer delegates restore to a plural-restorer-<runtime> executable.
+//
+// The plugin is invoked with a single verb argument:
+//
+//	prepare  reads RestoreOptions JSON on stdin and writes a PreparedSession JSON to stdout
+//	resume   runs with the terminal attached and the PreparedSession JSON in PLURAL_PREPARED_SESSION
+//	package  reads PackageOptions JSON on stdin and writes {"archivePath": "..."} to stdout
+//
+// Plugins should log to stderr, which is passed through to the user.
+type PluginRestorer struct {
+	// provider is the runtime type the plugin was resolved for.
+	provider console.AgentRuntimeType
+	// path is the resolved plugin executable.
+	path string
+}
+
+// NewPluginRestorer returns a restorer backed by the plugin executable at path.
+func NewPluginRestorer(provider console.AgentRuntimeType, path string) *PluginRestorer {
+	return &PluginRestorer{provider: provider, path: path}
+}
+
+func (r *PluginRestorer) Provider() console.AgentRuntimeType { return r.provider }
+
+func (r *PluginRestorer) Prepare(ctx context.Context, opts RestoreOptions) (*PreparedSession, error) {
+	var prepared PreparedSession
+	if err := r.call(ctx, opts.RepoPath, "prepare", opts, &prepared); err != nil {
+		return nil, err
+	}
+	if prepared.RepoPath == "" {
+		prepared.RepoPath = opts.RepoPath
+	}
+	if prepared.WorkDir == "" {
+		prepared.WorkDir = opts.WorkDir
+	}
+	return &prepared, nil
+}
+
+func (r *PluginRestorer) Resume(ctx context.Context, prepared *PreparedSession) error {
+	data, err := json.Marshal(prepared)
+	if err != nil {
+		return err
+	}
+	return Executable(prepared.RepoPath, r.env(PreparedSessionEnv+"="+string(data))...).Run(ctx, r.path, "resume")
+}
+
+func (r *PluginRestorer) Package(ctx context.Context, opts PackageOptions) (string, error) {
+	var packaged struct {
+		ArchivePath string `json:"archivePath"`
+	}
+	if err := r.call(ctx, opts.RepoPath, "package", opts, &packaged); err != nil {
+		return "", err
+	}
+	if packaged.ArchivePath == "" {
+		return "", fmt.Errorf("restorer plugin %s returned an empty archivePath", r.path)
+	}
+	return packaged.ArchivePath, nil
+}
+
+func (r *PluginRestorer) call(ctx context.Context, dir, verb string, in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.path, verb)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), r.env()...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("restorer plugin %s %s: %w", r.path, verb, err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), out); err != nil {
+		return fmt.Errorf("restorer plugin %s %s returned invalid JSON: %w", r.path, verb, err)
+	}
+	return nil
+}
+
+func (r *PluginRestorer) env(extra ...string) []string {
+	return append([]string{"PLURAL_RESTORER_PROTOCOL=" + RestorerPluginProtocol}, extra...)
+}
+
+// PluginRestorerRegistry resolves restorers from a base registry, falling back
+// to plural-restorer-<runtime> executables on PATH for other runtimes.
+type PluginRestorerRegistry struct {
+	// base holds the built-in restorers, which take precedence over plugins.
+	base RestorerRegistry
+	// lookPath resolves plugin executables, exec.LookPath outside of tests.
+	lookPath func(string) (string, error)
+}
+
+// NewPluginRestorerRegistry wraps base with plugin discovery.
+func NewPluginRestorerRegistry(base RestorerRegistry) *PluginRestorerRegistry {
+	return &PluginRestorerRegistry{base: base, lookPath: exec.LookPath}
+}
+
+func (r *PluginRestorerRegistry) ForProvider(provider console.AgentRuntimeType) (SessionRestorer, error) {
+	restorer, err := r.base.ForProvider(provider)
+	if err == nil {
+		return restorer, nil
+	}
+	if !pluginRuntimeName.MatchString(string(provider)) {
+		return nil, err
+	}
+
+	name := RestorerPluginPrefix + strings.ToLower(string(provider))
+	path, lookErr := r.lookPath(name)
+	if lookErr != nil {
+		return nil, fmt.Errorf("unsupported session provider %q, install a %s executable on your PATH to restore it", provider, name)
+	}
+	return NewPluginRestorer(provider, path), nil
+}
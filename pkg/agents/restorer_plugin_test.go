@@ -0,0 +1,94 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	console "github.com/pluralsh/console/go/client"
+)
+
+const testRestorerPlugin = `#!/bin/sh
+case "$1" in
+  prepare)
+    cat > "$PLUGIN_OUT/prepare.json"
+    echo '{"sessionId":"plugin-session","workDir":"/tmp/plugin"}'
+    ;;
+  resume)
+    printf '%s' "$PLURAL_PREPARED_SESSION" > "$PLUGIN_OUT/resume.json"
+    ;;
+  *)
+    exit 1
+    ;;
+esac
+`
+
+func TestPluginRestorerRegistryResolvesPluginsFromPath(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	bin := t.TempDir()
+	out := t.TempDir()
+	writeFile(t, filepath.Join(bin, RestorerPluginPrefix+"aider"), testRestorerPlugin)
+	if err := os.Chmod(filepath.Join(bin, RestorerPluginPrefix+"aider"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("PLUGIN_OUT", out)
+
+	var manifest SessionManifest
+	if err := json.Unmarshal([]byte(`{"version":1,"agentRunId":"run-1","provider":"aider","session":{"id":"session-id"}}`), &manifest); err != nil {
+		t.Fatalf("expected plugin provider to be accepted: %v", err)
+	}
+
+	registry := NewPluginRestorerRegistry(NewRestorerRegistry())
+	restorer, err := registry.ForProvider(manifest.Provider)
+	if err != nil {
+		t.Fatalf("ForProvider returned error: %v", err)
+	}
+
+	repoPath := t.TempDir()
+	prepared, err := restorer.Prepare(context.Background(), RestoreOptions{
+		RepoPath:    repoPath,
+		ArchivePath: "/tmp/session.tar.gz",
+		Manifest:    &manifest,
+	})
+	if err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	if prepared.SessionID != "plugin-session" || prepared.WorkDir != "/tmp/plugin" || prepared.RepoPath != repoPath {
+		t.Fatalf("unexpected prepared session: %#v", prepared)
+	}
+
+	var opts RestoreOptions
+	data, err := os.ReadFile(filepath.Join(out, "prepare.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		t.Fatalf("plugin received invalid options: %v", err)
+	}
+	if opts.ArchivePath != "/tmp/session.tar.gz" || opts.Manifest.Provider != console.AgentRuntimeType("AIDER") {
+		t.Fatalf("unexpected options passed to plugin: %#v", opts)
+	}
+
+	if err := restorer.Resume(context.Background(), prepared); err != nil {
+		t.Fatalf("Resume returned error: %v", err)
+	}
+	var resumed PreparedSession
+	data, err = os.ReadFile(filepath.Join(out, "resume.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &resumed); err != nil || resumed.SessionID != "plugin-session" {
+		t.Fatalf("expected resume to receive the prepared session, got %#v %v", resumed, err)
+	}
+
+	if _, err := registry.ForProvider("MISSING"); err == nil {
+		t.Fatalf("expected a missing plugin to fail")
+	}
+}
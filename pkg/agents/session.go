@@ -60,7 +60,7 @@ func NewSessionService(options ...SessionServiceOption) *SessionService {
 	service := &SessionService{
 		store:       NewHTTPArchiveStore(nil),
 		archive:     archive,
-		registry:    NewDefaultRestorerRegistry(archive),
+		registry:    NewPluginRestorerRegistry(NewDefaultRestorerRegistry(archive)),
 		interaction: interaction,
 	}
 	for _, option := range options {
@@ -9,85 +9,29 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/mitchellh/go-homedir"
 	"github.com/pluralsh/plural-cli/pkg/api"
+	"github.com/pluralsh/plural-cli/pkg/condition"
 	"github.com/pluralsh/plural-cli/pkg/manifest"
 	"github.com/pluralsh/plural-cli/pkg/utils"
 )
 
-type OperationType struct {
-	Operation string
-	Type      string
-}
-
-func evaluateCondition(ctx map[string]interface{}, cond *api.Condition, valueType string) bool {
+// evaluateCondition checks whether an item is enabled, conditions referencing values of an unexpected type
+// are reported as errors
+func evaluateCondition(ctx map[string]interface{}, cond *api.Condition) (bool, error) {
 	if cond == nil {
-		return true
-	}
-
-	val, ok := ctx[cond.Field]
-	if !ok {
-		return cond.Operation == "NOT"
+		return true, nil
 	}
 
-	condValue := cond.Value
-	operationType := OperationType{
-		Operation: cond.Operation,
-		Type:      valueType,
-	}
-
-	if operationType.Operation == "NOT" {
-		booled, ok := val.(bool)
-		return ok && !booled // it must be a false boolean value
-	}
-
-	if operationType.Operation == "PREFIX" {
-		return strings.HasPrefix(val.(string), condValue)
-	}
-
-	if operationType.Operation == "SUFFIX" {
-		return strings.HasSuffix(val.(string), condValue)
-	}
-
-	switch operationType {
-	case OperationType{Operation: "EQ", Type: String}:
-		return val == condValue
-	case OperationType{Operation: "EQ", Type: Int}:
-		intCondValue, err := strconv.Atoi(condValue)
-		if err != nil {
-			return false
-		}
-		return val.(int) == intCondValue
-	case OperationType{Operation: "GT", Type: Int}:
-		intCondValue, err := strconv.Atoi(condValue)
-		if err != nil {
-			return false
-		}
-		return val.(int) > intCondValue
-	case OperationType{Operation: "GTE", Type: Int}:
-		intCondValue, err := strconv.Atoi(condValue)
-		if err != nil {
-			return false
-		}
-		return val.(int) >= intCondValue
-	case OperationType{Operation: "LT", Type: Int}:
-		intCondValue, err := strconv.Atoi(condValue)
-		if err != nil {
-			return false
-		}
-		return val.(int) < intCondValue
-	case OperationType{Operation: "LTE", Type: Int}:
-		intCondValue, err := strconv.Atoi(condValue)
-		if err != nil {
-			return false
-		}
-		return val.(int) <= intCondValue
+	expr, err := condition.FromCondition(cond.Field, cond.Operation, cond.Value)
+	if err != nil {
+		return false, err
 	}
-
-	return true
+	return expr.Evaluate(ctx)
 }
 
 func Configure(ctx map[string]interface{}, item *api.ConfigurationItem, context *manifest.Context, repo string) (err error) {
-	if !evaluateCondition(ctx, item.Condition, item.Type) {
-		return
+	enabled, err := evaluateCondition(ctx, item.Condition)
+	if err != nil || !enabled {
+		return err
 	}
 
 	if item.Type == Function {
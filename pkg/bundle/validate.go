@@ -0,0 +1,137 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pluralsh/plural-cli/pkg/api"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+// ValidateConfiguration is the non-interactive counterpart of Configure. It checks the supplied values
+// against the configuration items in order, coercing them to the item type and filling in defaults.
+// Items whose condition does not hold are skipped, values without a matching item are passed through
+// untouched. Every invalid or missing value is reported in the returned error.
+func ValidateConfiguration(items []*api.ConfigurationItem, values map[string]interface{}) (map[string]interface{}, error) {
+	ctx := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		ctx[name] = value
+	}
+
+	errs := make([]error, 0)
+	for _, item := range items {
+		value, supplied := values[item.Name]
+		if supplied && value == nil {
+			supplied = false
+		}
+		delete(ctx, item.Name)
+
+		enabled, err := evaluateCondition(ctx, item.Condition)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Name, err))
+			continue
+		}
+		if !enabled {
+			continue
+		}
+
+		if !supplied || value == "" {
+			if item.Default == "" {
+				if !item.Optional && item.Type != Function {
+					errs = append(errs, fmt.Errorf("%s: a value is required", item.Name))
+				}
+				continue
+			}
+			value = item.Default
+		}
+
+		coerced, err := coerceValue(item, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Name, err))
+			continue
+		}
+		ctx[item.Name] = coerced
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return ctx, nil
+}
+
+func coerceValue(item *api.ConfigurationItem, value interface{}) (interface{}, error) {
+	switch item.Type {
+	case Int:
+		return coerceInt(value)
+	case Bool:
+		return coerceBool(value)
+	case Enum:
+		res := fmt.Sprint(value)
+		if len(item.Values) == 0 {
+			return nil, fmt.Errorf("no values defined")
+		}
+		for _, allowed := range item.Values {
+			if res == allowed {
+				return res, nil
+			}
+		}
+		return nil, fmt.Errorf("%q must be one of %s", res, strings.Join(item.Values, ", "))
+	default:
+		res, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %v", value)
+		}
+		if item.Validation != nil && item.Validation.Regex != "" {
+			if err := matchRegex(res, item.Validation); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+}
+
+func coerceInt(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		return int(v), nil
+	case string:
+		res, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", v)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("%v is not an integer", value)
+}
+
+func coerceBool(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		res, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", v)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("%v is not a boolean", value)
+}
+
+func matchRegex(value string, validation *api.Validation) error {
+	message := validation.Message
+	if message == "" {
+		message = fmt.Sprintf("%q does not match %s", value, validation.Regex)
+	}
+	return utils.ValidateRegex(value, validation.Regex, message)
+}
@@ -0,0 +1,70 @@
+package bundle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pluralsh/plural-cli/pkg/api"
+	"github.com/pluralsh/plural-cli/pkg/bundle"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	items := []*api.ConfigurationItem{
+		{Name: "name", Type: bundle.String, Validation: &api.Validation{Regex: "[a-z][a-z-0-9]+"}},
+		{Name: "replicas", Type: bundle.Int, Default: "2"},
+		{Name: "ha", Type: bundle.Bool, Optional: true},
+		{Name: "type", Type: bundle.Enum, Values: []string{"s3", "gcs"}},
+		{
+			Name:      "bucket",
+			Type:      bundle.String,
+			Condition: &api.Condition{Field: "type", Operation: "EQ", Value: "s3"},
+		},
+		{Name: "notes", Type: bundle.String, Optional: true},
+	}
+
+	tests := []struct {
+		name          string
+		values        map[string]interface{}
+		expected      map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:   "coerces values and fills defaults",
+			values: map[string]interface{}{"name": "blob", "ha": "true", "type": "s3", "bucket": "my-bucket", "extra": "kept"},
+			expected: map[string]interface{}{
+				"name": "blob", "replicas": 2, "ha": true, "type": "s3", "bucket": "my-bucket", "extra": "kept",
+			},
+		},
+		{
+			name:     "skips items whose condition does not hold",
+			values:   map[string]interface{}{"name": "blob", "replicas": 3.0, "type": "gcs", "bucket": "ignored"},
+			expected: map[string]interface{}{"name": "blob", "replicas": 3, "type": "gcs"},
+		},
+		{
+			name:   "reports every error at once",
+			values: map[string]interface{}{"name": "Blob", "replicas": "two", "ha": "maybe", "type": "azure"},
+			expectedError: "invalid configuration:\n" +
+				"name: Validation Failure: \"Blob\" does not match [a-z][a-z-0-9]+\n" +
+				"replicas: \"two\" is not an integer\n" +
+				"ha: \"maybe\" is not a boolean\n" +
+				"type: \"azure\" must be one of s3, gcs",
+		},
+		{
+			name:          "requires values without defaults",
+			values:        map[string]interface{}{"type": "s3"},
+			expectedError: "invalid configuration:\nname: a value is required\nbucket: a value is required",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, err := bundle.ValidateConfiguration(items, test.values)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, ctx)
+		})
+	}
+}
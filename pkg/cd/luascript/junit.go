@@ -0,0 +1,102 @@
+package luascript
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results in the JUnit xml format understood by most CI systems.
+func WriteJUnit(w io.Writer, suites []*TestSuite) error {
+	report := junitSuites{Suites: make([]junitSuite, 0, len(suites))}
+	var total time.Duration
+	for _, suite := range suites {
+		js := junitSuite{
+			Name:     suite.File,
+			Tests:    len(suite.Cases),
+			Failures: suite.Failures(),
+			Time:     seconds(suite.Duration),
+			Cases:    make([]junitCase, 0, len(suite.Cases)),
+		}
+		for _, c := range suite.Cases {
+			jc := junitCase{Name: c.Name, ClassName: suite.File, Time: seconds(c.Duration)}
+			if c.Failure != "" {
+				jc.Failure = &junitMessage{Message: firstLine(c.Failure), Body: c.Failure}
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		if suite.Error != "" {
+			js.Tests++
+			js.Errors++
+			js.Cases = append(js.Cases, junitCase{
+				Name:      "load",
+				ClassName: suite.File,
+				Time:      seconds(0),
+				Error:     &junitMessage{Message: firstLine(suite.Error), Body: suite.Error},
+			})
+		}
+
+		report.Tests += js.Tests
+		report.Failures += js.Failures
+		report.Errors += js.Errors
+		total += suite.Duration
+		report.Suites = append(report.Suites, js)
+	}
+	report.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func firstLine(s string) string {
+	for i, c := range s {
+		if c == '\n' {
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+package luascript_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/cd/luascript"
+)
+
+func readScript(t *testing.T, names ...string) *luascript.Script {
+	t.Helper()
+	files := make([]luascript.File, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join("testdata", name)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		files = append(files, luascript.File{Name: path, Content: string(content)})
+	}
+	return luascript.NewScript(files...)
+}
+
+func TestExecuteTrace(t *testing.T) {
+	script := readScript(t, "lib.lua", "service.lua")
+	mutations := make([]string, 0)
+	res, err := luascript.Execute(script, luascript.Options{
+		Dir:      t.TempDir(),
+		Bindings: map[string]interface{}{"cluster": map[string]interface{}{"tier": "prod"}},
+		Trace: func(m luascript.Mutation) {
+			mutations = append(mutations, m.String())
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"replicas": float64(3), "image": map[string]interface{}{"repository": "nginx"}}, res.Values)
+	assert.Equal(t, []string{"prod.yaml"}, res.ValuesFiles)
+
+	service := filepath.Join("testdata", "service.lua")
+	assert.Equal(t, []string{
+		service + ":1: values.replicas = 3",
+		service + `:2: values.image.repository = "nginx"`,
+		service + `:3: valuesFiles[1] = "prod.yaml"`,
+	}, mutations)
+}
+
+func TestExecuteErrorPositions(t *testing.T) {
+	script := readScript(t, "lib.lua", "service.lua")
+	_, err := luascript.Execute(script, luascript.Options{Dir: t.TempDir()})
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "testdata/lib.lua:2: attempt to index a non-table object(nil) with key 'tier'"), err.Error())
+
+	_, err = luascript.Execute(luascript.NewScript(luascript.File{Name: "broken.lua", Content: "values.a = = 1"}), luascript.Options{})
+	assert.EqualError(t, err, "broken.lua:1(column:12) near '=':   syntax error\n")
+}
+
+func TestRepl(t *testing.T) {
+	in := strings.NewReader("values.replicas = replicas(cluster)\nreplicas(\n{ tier = 'dev' })\n:values\nunknown.field\n:quit\n")
+	var out bytes.Buffer
+	err := luascript.Repl(readScript(t, "lib.lua"), luascript.Options{
+		Dir:      t.TempDir(),
+		Bindings: map[string]interface{}{"cluster": map[string]interface{}{"tier": "prod"}},
+	}, in, &out)
+	require.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "lua> >> 1\n")
+	assert.Contains(t, output, "values = {\"replicas\":3}\nvaluesFiles = []\n")
+	assert.Contains(t, output, "attempt to index a non-table object(nil) with key 'field'")
+}
+
+func TestRunTests(t *testing.T) {
+	files, err := luascript.FindTests([]string{"testdata"})
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join("testdata", "service_test.lua")}, files)
+
+	suites := luascript.RunTests(files, luascript.TestOptions{
+		Script:  readScript(t, "lib.lua", "service.lua"),
+		Library: readScript(t, "lib.lua").Files,
+		Dir:     t.TempDir(),
+	})
+	require.Len(t, suites, 1)
+	require.Empty(t, suites[0].Error)
+
+	names := make([]string, 0)
+	for _, c := range suites[0].Cases {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"test_prod", "test_dev", "test_library"}, names)
+	assert.Empty(t, suites[0].Cases[0].Failure)
+	assert.Contains(t, suites[0].Cases[1].Failure, "service_test.lua:10: assertion failed: dev replicas: expected 3, got 1")
+	assert.Empty(t, suites[0].Cases[2].Failure)
+	assert.Equal(t, 1, suites[0].Failures())
+
+	var report bytes.Buffer
+	require.NoError(t, luascript.WriteJUnit(&report, suites))
+	assert.Contains(t, report.String(), `<testsuites tests="3" failures="1" errors="0"`)
+	assert.Contains(t, report.String(), `<testcase name="test_dev" classname="testdata/service_test.lua"`)
+	assert.Contains(t, report.String(), `<failure message="testdata/service_test.lua:10: assertion failed: dev replicas: expected 3, got 1">`)
+}
@@ -0,0 +1,125 @@
+package luascript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pluralsh/polly/luautils"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+const (
+	replChunkName = "<repl>"
+	replHelp      = `enter lua statements or expressions, expressions print their value
+  :values  print the current values and valuesFiles
+  :help    print this message
+  :quit    exit the repl
+`
+)
+
+// Repl reads lua from in until it's closed or :quit is entered. The script runs first, so its functions and
+// values are available, and errors are printed instead of ending the session.
+func Repl(script *Script, opts Options, in io.Reader, out io.Writer) error {
+	L := NewState(opts.Dir, opts.Bindings)
+	defer L.Close()
+
+	if len(script.Files) > 0 {
+		if err := script.Run(L, opts.Trace); err != nil {
+			fmt.Fprintf(out, "%s\n", err)
+		}
+	}
+
+	fmt.Fprint(out, replHelp)
+	scanner := bufio.NewScanner(in)
+	var buffer []string
+	for {
+		if len(buffer) == 0 {
+			fmt.Fprint(out, "lua> ")
+		} else {
+			fmt.Fprint(out, ">> ")
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		line := scanner.Text()
+		if len(buffer) == 0 {
+			switch strings.TrimSpace(line) {
+			case "":
+				continue
+			case ":quit", ":q", "exit":
+				return nil
+			case ":help":
+				fmt.Fprint(out, replHelp)
+				continue
+			case ":values":
+				res, err := result(L)
+				if err != nil {
+					fmt.Fprintf(out, "%s\n", err)
+					continue
+				}
+				fmt.Fprintf(out, "values = %s\nvaluesFiles = %s\n", format(res.Values), format(res.ValuesFiles))
+				continue
+			}
+		}
+
+		buffer = append(buffer, line)
+		source := strings.Join(buffer, "\n")
+		results, err := eval(L, source)
+		if err != nil && strings.Contains(err.Error(), "at EOF") {
+			// the statement continues on the next line
+			continue
+		}
+		buffer = nil
+
+		if err != nil {
+			fmt.Fprintf(out, "%s\n", err)
+			continue
+		}
+		for _, res := range results {
+			fmt.Fprintln(out, format(luautils.ToGoValue(res)))
+		}
+	}
+}
+
+// eval runs source as an expression if it is one, otherwise as a block of statements
+func eval(L *lua.LState, source string) ([]lua.LValue, error) {
+	chunk, err := parse.Parse(strings.NewReader("return "+source), replChunkName)
+	if err != nil {
+		if chunk, err = parse.Parse(strings.NewReader(source), replChunkName); err != nil {
+			return nil, err
+		}
+	}
+
+	proto, err := lua.Compile(chunk, replChunkName)
+	if err != nil {
+		return nil, err
+	}
+
+	top := L.GetTop()
+	L.Push(L.NewFunctionFromProto(proto))
+	if err := L.PCall(0, lua.MultRet, nil); err != nil {
+		return nil, err
+	}
+
+	results := make([]lua.LValue, 0, L.GetTop()-top)
+	for i := top + 1; i <= L.GetTop(); i++ {
+		results = append(results, L.Get(i))
+	}
+	L.SetTop(top)
+	return results, nil
+}
+
+// format prints a value the way it would be written to the helm values
+func format(value interface{}) string {
+	res, err := json.Marshal(luautils.SanitizeValue(value))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(res)
+}
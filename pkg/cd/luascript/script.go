@@ -0,0 +1,182 @@
+// Package luascript runs the lua scripts of services the way the deploy agent does, with tracing, a repl and a
+// test runner on top to debug them locally.
+package luascript
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pluralsh/polly/luautils"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+const chunkName = "<script>"
+
+var positionRegex = regexp.MustCompile(regexp.QuoteMeta(chunkName) + `(:| line:)(\d+)`)
+
+// File is a named piece of lua source, names are used to report positions.
+type File struct {
+	Name    string
+	Content string
+}
+
+// Script concatenates library files and the main script into the single chunk the deploy agent executes.
+type Script struct {
+	Files []File
+}
+
+type Options struct {
+	Dir      string
+	Bindings map[string]interface{}
+	// Trace receives every change of values and valuesFiles, along with the line that made it.
+	Trace func(Mutation)
+}
+
+type Result struct {
+	Values      map[string]interface{}
+	ValuesFiles []string
+}
+
+func NewScript(files ...File) *Script {
+	return &Script{Files: files}
+}
+
+// Folder reads every .lua file below folder in lexical order.
+func Folder(folder string) ([]File, error) {
+	paths := make([]string, 0)
+	if err := filepath.WalkDir(folder, func(path string, info iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".lua") {
+			paths = append(paths, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to walk lua folder %s: %w", folder, err)
+	}
+	sort.Strings(paths)
+
+	files := make([]File, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			rel, _ := filepath.Rel(folder, path)
+			return nil, fmt.Errorf("failed to read lua file %s: %w", rel, err)
+		}
+		files = append(files, File{Name: path, Content: string(content)})
+	}
+	return files, nil
+}
+
+func (s *Script) String() string {
+	contents := make([]string, 0, len(s.Files))
+	for _, f := range s.Files {
+		contents = append(contents, f.Content)
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+// Position maps a line of the concatenated script back onto the file it came from.
+func (s *Script) Position(line int) string {
+	start := 1
+	for _, f := range s.Files {
+		next := start + strings.Count(f.Content, "\n") + 2
+		if line < next {
+			return fmt.Sprintf("%s:%d", f.Name, line-start+1)
+		}
+		start = next
+	}
+	return fmt.Sprintf("%s:%d", chunkName, line)
+}
+
+// Error rewrites the positions in a lua error to the files of the script.
+func (s *Script) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s", positionRegex.ReplaceAllStringFunc(err.Error(), func(match string) string {
+		groups := positionRegex.FindStringSubmatch(match)
+		line, _ := strconv.Atoi(groups[2])
+		return s.Position(line)
+	}))
+}
+
+// NewState creates a lua state with the service bindings and empty values and valuesFiles tables.
+func NewState(dir string, bindings map[string]interface{}) *lua.LState {
+	L := luautils.NewLuaState(dir)
+	L.SetGlobal("values", L.NewTable())
+	L.SetGlobal("valuesFiles", L.NewTable())
+	for name, binding := range bindings {
+		L.SetGlobal(name, luautils.GoValueToLuaValue(L, binding))
+	}
+	return L
+}
+
+// Execute runs the script and returns the values and valuesFiles it produced.
+func Execute(script *Script, opts Options) (*Result, error) {
+	L := NewState(opts.Dir, opts.Bindings)
+	defer L.Close()
+
+	if err := script.Run(L, opts.Trace); err != nil {
+		return nil, err
+	}
+	return result(L)
+}
+
+// Run executes the script in an existing state, tracing changes of values and valuesFiles if trace is set.
+func (s *Script) Run(L *lua.LState, trace func(Mutation)) error {
+	chunk, err := parse.Parse(strings.NewReader(s.String()), chunkName)
+	if err != nil {
+		return s.Error(err)
+	}
+
+	if trace != nil {
+		tracer := &tracer{script: s, trace: trace, previous: snapshot(L)}
+		L.SetGlobal(traceFunction, L.NewFunction(tracer.call))
+		chunk = instrument(chunk)
+	}
+
+	proto, err := lua.Compile(chunk, chunkName)
+	if err != nil {
+		return s.Error(err)
+	}
+	L.Push(L.NewFunctionFromProto(proto))
+	return s.Error(L.PCall(0, lua.MultRet, nil))
+}
+
+func result(L *lua.LState) (*Result, error) {
+	values := map[interface{}]interface{}{}
+	valuesFiles := []string{}
+
+	valuesTable, ok := L.GetGlobal("values").(*lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("values must be a table")
+	}
+	if err := luautils.MapLua(valuesTable, &values); err != nil {
+		return nil, err
+	}
+
+	valuesFilesTable, ok := L.GetGlobal("valuesFiles").(*lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("valuesFiles must be a table")
+	}
+	if err := luautils.MapLua(valuesFilesTable, &valuesFiles); err != nil {
+		return nil, err
+	}
+
+	if valuesFiles == nil {
+		valuesFiles = []string{}
+	}
+	return &Result{
+		Values:      luautils.SanitizeValue(values).(map[string]interface{}),
+		ValuesFiles: valuesFiles,
+	}, nil
+}
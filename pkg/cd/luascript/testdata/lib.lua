@@ -0,0 +1,6 @@
+function replicas(cluster)
+  if cluster.tier == "prod" then
+    return 3
+  end
+  return 1
+end
@@ -0,0 +1,3 @@
+values.replicas = replicas(cluster)
+values.image = { repository = "nginx" }
+valuesFiles[1] = cluster.tier .. ".yaml"
@@ -0,0 +1,16 @@
+function test_prod()
+  local values, valuesFiles = render("fixtures/prod.yaml")
+  assert_eq(values.replicas, 3)
+  assert_eq(values.image, { repository = "nginx" })
+  assert_contains(valuesFiles, "prod.yaml")
+end
+
+function test_dev()
+  local values = render({ cluster = { tier = "dev" } })
+  assert_eq(values.replicas, 3, "dev replicas")
+end
+
+function test_library()
+  assert_eq(replicas({ tier = "prod" }), 3)
+  assert_error(function() render({}) end, "attempt to index")
+end
@@ -0,0 +1,282 @@
+package luascript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pluralsh/polly/luautils"
+	lua "github.com/yuin/gopher-lua"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	testFileSuffix = "_test.lua"
+	testPrefix     = "test"
+)
+
+type TestOptions struct {
+	// Script is the service script run by render().
+	Script *Script
+	// Library files are loaded into every test file before it runs.
+	Library []File
+	// Dir is the working directory of the service script.
+	Dir string
+}
+
+type TestCase struct {
+	Name     string
+	Duration time.Duration
+	// Failure is the message of the failed assertion or error, empty if the test passed.
+	Failure string
+}
+
+type TestSuite struct {
+	File     string
+	Duration time.Duration
+	Cases    []TestCase
+	// Error is set when the test file itself fails to load.
+	Error string
+}
+
+func (s *TestSuite) Failures() int {
+	failures := 0
+	for _, c := range s.Cases {
+		if c.Failure != "" {
+			failures++
+		}
+	}
+	return failures
+}
+
+// FindTests returns every *_test.lua file below the given paths.
+func FindTests(paths []string) ([]string, error) {
+	files := make([]string, 0)
+	for _, path := range paths {
+		if err := filepath.WalkDir(path, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(d.Name(), testFileSuffix) {
+				files = append(files, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// RunTests runs every global function starting with test in each file, in the order they are defined. Each file
+// gets its own lua state with the assertion helpers, render() and fixture() registered.
+func RunTests(files []string, opts TestOptions) []*TestSuite {
+	suites := make([]*TestSuite, 0, len(files))
+	for _, file := range files {
+		start := time.Now()
+		suite := runTestFile(file, opts)
+		suite.Duration = time.Since(start)
+		suites = append(suites, suite)
+	}
+	return suites
+}
+
+func runTestFile(file string, opts TestOptions) *TestSuite {
+	suite := &TestSuite{File: file, Cases: make([]TestCase, 0)}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		suite.Error = err.Error()
+		return suite
+	}
+
+	L := luautils.NewLuaState(filepath.Dir(file))
+	defer L.Close()
+	registerHelpers(L, filepath.Dir(file), opts)
+
+	if len(opts.Library) > 0 {
+		if err := NewScript(opts.Library...).Run(L, nil); err != nil {
+			suite.Error = fmt.Sprintf("failed to load library: %s", err)
+			return suite
+		}
+	}
+
+	fn, err := L.Load(strings.NewReader(string(content)), file)
+	if err == nil {
+		L.Push(fn)
+		err = L.PCall(0, lua.MultRet, nil)
+	}
+	if err != nil {
+		suite.Error = err.Error()
+		return suite
+	}
+
+	for _, test := range testFunctions(L) {
+		start := time.Now()
+		L.Push(test.fn)
+		err := L.PCall(0, 0, nil)
+		tc := TestCase{Name: test.name, Duration: time.Since(start)}
+		if err != nil {
+			tc.Failure = err.Error()
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return suite
+}
+
+type testFunction struct {
+	name string
+	fn   *lua.LFunction
+}
+
+func testFunctions(L *lua.LState) []testFunction {
+	tests := make([]testFunction, 0)
+	L.G.Global.ForEach(func(key, value lua.LValue) {
+		name, ok := key.(lua.LString)
+		if !ok || !strings.HasPrefix(string(name), testPrefix) {
+			return
+		}
+		if fn, ok := value.(*lua.LFunction); ok && fn.Proto != nil {
+			tests = append(tests, testFunction{name: string(name), fn: fn})
+		}
+	})
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].fn.Proto.LineDefined < tests[j].fn.Proto.LineDefined
+	})
+	return tests
+}
+
+func registerHelpers(L *lua.LState, dir string, opts TestOptions) {
+	fixture := func(L *lua.LState, path string) lua.LValue {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			L.RaiseError("failed to read fixture %s: %s", path, err)
+		}
+		ctx := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &ctx); err != nil {
+			L.RaiseError("failed to parse fixture %s: %s", path, err)
+		}
+		return luautils.GoValueToLuaValue(L, ctx)
+	}
+
+	helpers := map[string]lua.LGFunction{
+		"fixture": func(L *lua.LState) int {
+			L.Push(fixture(L, L.CheckString(1)))
+			return 1
+		},
+		// render runs the service script with a context table or fixture file and returns values and valuesFiles
+		"render": func(L *lua.LState) int {
+			if opts.Script == nil || len(opts.Script.Files) == 0 {
+				L.RaiseError("render needs a service script, pass one with --lua-file")
+			}
+
+			ctx := L.Get(1)
+			if path, ok := ctx.(lua.LString); ok {
+				ctx = fixture(L, string(path))
+			}
+			bindings := map[string]interface{}{}
+			if tbl, ok := ctx.(*lua.LTable); ok {
+				if err := luautils.MapLua(tbl, &bindings); err != nil {
+					L.RaiseError("invalid context: %s", err)
+				}
+			}
+
+			res, err := Execute(opts.Script, Options{Dir: opts.Dir, Bindings: bindings})
+			if err != nil {
+				L.RaiseError("render failed: %s", err)
+			}
+			L.Push(luautils.GoValueToLuaValue(L, res.Values))
+			L.Push(luautils.GoValueToLuaValue(L, res.ValuesFiles))
+			return 2
+		},
+		"assert_eq": func(L *lua.LState) int {
+			actual, expected := luautils.ToGoValue(L.Get(1)), luautils.ToGoValue(L.Get(2))
+			if !reflect.DeepEqual(actual, expected) {
+				fail(L, 3, "expected %s, got %s", format(expected), format(actual))
+			}
+			return 0
+		},
+		"assert_neq": func(L *lua.LState) int {
+			actual, unexpected := luautils.ToGoValue(L.Get(1)), luautils.ToGoValue(L.Get(2))
+			if reflect.DeepEqual(actual, unexpected) {
+				fail(L, 3, "expected a value other than %s", format(unexpected))
+			}
+			return 0
+		},
+		"assert_true": func(L *lua.LState) int {
+			if !lua.LVAsBool(L.Get(1)) {
+				fail(L, 2, "expected a truthy value, got %s", format(luautils.ToGoValue(L.Get(1))))
+			}
+			return 0
+		},
+		"assert_false": func(L *lua.LState) int {
+			if lua.LVAsBool(L.Get(1)) {
+				fail(L, 2, "expected a falsy value, got %s", format(luautils.ToGoValue(L.Get(1))))
+			}
+			return 0
+		},
+		"assert_nil": func(L *lua.LState) int {
+			if L.Get(1) != lua.LNil {
+				fail(L, 2, "expected nil, got %s", format(luautils.ToGoValue(L.Get(1))))
+			}
+			return 0
+		},
+		"assert_not_nil": func(L *lua.LState) int {
+			if L.Get(1) == lua.LNil {
+				fail(L, 2, "expected a value, got nil")
+			}
+			return 0
+		},
+		"assert_contains": func(L *lua.LState) int {
+			if !contains(L.Get(1), L.Get(2)) {
+				fail(L, 3, "expected %s to contain %s", format(luautils.ToGoValue(L.Get(1))), format(luautils.ToGoValue(L.Get(2))))
+			}
+			return 0
+		},
+		"assert_error": func(L *lua.LState) int {
+			fn := L.CheckFunction(1)
+			L.Push(fn)
+			err := L.PCall(0, 0, nil)
+			if err == nil {
+				fail(L, 3, "expected an error")
+			}
+			if pattern := L.OptString(2, ""); pattern != "" && !strings.Contains(err.Error(), pattern) {
+				fail(L, 3, "expected an error containing %q, got %s", pattern, err)
+			}
+			return 0
+		},
+	}
+	for name, fn := range helpers {
+		L.SetGlobal(name, L.NewFunction(fn))
+	}
+}
+
+// fail raises an assertion error at the caller, prefixed with the optional message argument
+func fail(L *lua.LState, msgArg int, msgFormat string, args ...interface{}) {
+	msg := fmt.Sprintf(msgFormat, args...)
+	if custom := L.OptString(msgArg, ""); custom != "" {
+		msg = fmt.Sprintf("%s: %s", custom, msg)
+	}
+	L.RaiseError("assertion failed: %s", msg)
+}
+
+func contains(container, item lua.LValue) bool {
+	switch c := container.(type) {
+	case lua.LString:
+		s, ok := item.(lua.LString)
+		return ok && strings.Contains(string(c), string(s))
+	case *lua.LTable:
+		expected := luautils.ToGoValue(item)
+		found := false
+		c.ForEach(func(_, value lua.LValue) {
+			found = found || reflect.DeepEqual(luautils.ToGoValue(value), expected)
+		})
+		return found
+	}
+	return false
+}
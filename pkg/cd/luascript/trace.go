@@ -0,0 +1,201 @@
+package luascript
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pluralsh/polly/luautils"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+)
+
+const traceFunction = "__plural_trace"
+
+// Mutation is a change of a single path below values or valuesFiles. Old is nil for new paths and New is nil
+// for removed ones.
+type Mutation struct {
+	Position string
+	Path     string
+	Old      interface{}
+	New      interface{}
+}
+
+func (m Mutation) String() string {
+	switch {
+	case m.Old == nil:
+		return fmt.Sprintf("%s: %s = %s", m.Position, m.Path, format(m.New))
+	case m.New == nil:
+		return fmt.Sprintf("%s: %s removed (was %s)", m.Position, m.Path, format(m.Old))
+	}
+	return fmt.Sprintf("%s: %s = %s (was %s)", m.Position, m.Path, format(m.New), format(m.Old))
+}
+
+type tracer struct {
+	script   *Script
+	trace    func(Mutation)
+	previous map[string]interface{}
+}
+
+// call runs after every statement, diffing values and valuesFiles against the last snapshot
+func (t *tracer) call(L *lua.LState) int {
+	line := L.CheckInt(1)
+	current := snapshot(L)
+
+	paths := make([]string, 0)
+	for path, value := range current {
+		if old, ok := t.previous[path]; !ok || !reflect.DeepEqual(old, value) {
+			paths = append(paths, path)
+		}
+	}
+	for path, old := range t.previous {
+		if _, ok := current[path]; !ok && !filled(path, old, current) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		t.trace(Mutation{Position: t.script.Position(line), Path: path, Old: t.previous[path], New: current[path]})
+	}
+	t.previous = current
+	return 0
+}
+
+// filled reports whether an empty table only disappeared from the snapshot because it got entries
+func filled(path string, old interface{}, current map[string]interface{}) bool {
+	if m, ok := old.(map[string]interface{}); !ok || len(m) > 0 {
+		return false
+	}
+	for p := range current {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot flattens values and valuesFiles into their leaf paths, empty tables are leaves as well
+func snapshot(L *lua.LState) map[string]interface{} {
+	leaves := map[string]interface{}{}
+	for _, name := range []string{"values", "valuesFiles"} {
+		flatten(name, luautils.ToGoValue(L.GetGlobal(name)), leaves)
+	}
+	return leaves
+}
+
+func flatten(path string, value interface{}, leaves map[string]interface{}) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		if len(v) == 0 {
+			leaves[path] = map[string]interface{}{}
+		}
+		for key, child := range v {
+			flatten(fmt.Sprintf("%s.%v", path, key), child, leaves)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(fmt.Sprintf("%s[%d]", path, i+1), child, leaves)
+		}
+	case nil:
+	default:
+		leaves[path] = v
+	}
+}
+
+// instrument calls the trace function after every statement, including the ones in function bodies
+func instrument(stmts []ast.Stmt) []ast.Stmt {
+	result := make([]ast.Stmt, 0, 2*len(stmts))
+	for _, stmt := range stmts {
+		instrumentStmt(stmt)
+		result = append(result, stmt)
+
+		switch stmt.(type) {
+		case *ast.ReturnStmt, *ast.BreakStmt, *ast.GotoStmt:
+			continue
+		}
+		result = append(result, traceStmt(stmt))
+	}
+	return result
+}
+
+// traceStmt reports the first line of the statement it follows, which is where multi-line statements start
+func traceStmt(after ast.Stmt) ast.Stmt {
+	line := after.Line()
+	arg := &ast.NumberExpr{Value: fmt.Sprint(line)}
+	fn := &ast.IdentExpr{Value: traceFunction}
+	call := &ast.FuncCallExpr{Func: fn, Args: []ast.Expr{arg}}
+	stmt := &ast.FuncCallStmt{Expr: call}
+	for _, node := range []ast.PositionHolder{arg, fn, call, stmt} {
+		node.SetLine(line)
+		node.SetLastLine(line)
+	}
+	return stmt
+}
+
+func instrumentStmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		instrumentExprs(s.Lhs...)
+		instrumentExprs(s.Rhs...)
+	case *ast.LocalAssignStmt:
+		instrumentExprs(s.Exprs...)
+	case *ast.FuncCallStmt:
+		instrumentExprs(s.Expr)
+	case *ast.DoBlockStmt:
+		s.Stmts = instrument(s.Stmts)
+	case *ast.WhileStmt:
+		instrumentExprs(s.Condition)
+		s.Stmts = instrument(s.Stmts)
+	case *ast.RepeatStmt:
+		instrumentExprs(s.Condition)
+		s.Stmts = instrument(s.Stmts)
+	case *ast.IfStmt:
+		instrumentExprs(s.Condition)
+		s.Then = instrument(s.Then)
+		s.Else = instrument(s.Else)
+	case *ast.NumberForStmt:
+		instrumentExprs(s.Init, s.Limit, s.Step)
+		s.Stmts = instrument(s.Stmts)
+	case *ast.GenericForStmt:
+		instrumentExprs(s.Exprs...)
+		s.Stmts = instrument(s.Stmts)
+	case *ast.FuncDefStmt:
+		instrumentExprs(s.Func)
+	case *ast.ReturnStmt:
+		instrumentExprs(s.Exprs...)
+	}
+}
+
+func instrumentExprs(exprs ...ast.Expr) {
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case *ast.FunctionExpr:
+			e.Stmts = instrument(e.Stmts)
+		case *ast.AttrGetExpr:
+			instrumentExprs(e.Object, e.Key)
+		case *ast.TableExpr:
+			for _, field := range e.Fields {
+				instrumentExprs(field.Key, field.Value)
+			}
+		case *ast.FuncCallExpr:
+			instrumentExprs(e.Func, e.Receiver)
+			instrumentExprs(e.Args...)
+		case *ast.LogicalOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.RelationalOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.StringConcatOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.ArithmeticOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.UnaryMinusOpExpr:
+			instrumentExprs(e.Expr)
+		case *ast.UnaryNotOpExpr:
+			instrumentExprs(e.Expr)
+		case *ast.UnaryLenOpExpr:
+			instrumentExprs(e.Expr)
+		}
+	}
+}
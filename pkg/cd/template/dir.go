@@ -0,0 +1,330 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pluralsh/console/go/polly/template"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+const maxIncludeDepth = 10
+
+var (
+	includeTag     = regexp.MustCompile(`{%-?\s*include\s+(.*?)\s*-?%}`)
+	includePath    = regexp.MustCompile(`^(?:"([^"]+)"|'([^']+)')$`)
+	manifestSource = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+)
+
+type DirOptions struct {
+	// Templated renders plain yaml files with liquid as well, like services with templated set.
+	Templated bool
+	// ValuesFiles are merged in order and passed to every helm chart in the folder.
+	ValuesFiles []string
+	ReleaseName string
+	Namespace   string
+}
+
+// Document is a rendered file, Source is its path relative to the rendered folder.
+type Document struct {
+	Source  string
+	Content []byte
+}
+
+// RenderDir renders a service folder locally. Liquid and tpl files are rendered, plain yaml is passed through and
+// folders with a Chart.yaml are templated with helm. Hidden files and folders are skipped.
+//
+// Liquid files can include other files with {% include "path" %}, where path is a quoted literal resolved relative
+// to the including file first and to the folder second. Included files are inlined before rendering so they see
+// the same bindings, and are not rendered on their own. Include parameters like {% include "x" with y %} and
+// variable paths are not supported and fail instead of rendering differently.
+func RenderDir(dir string, bindings map[string]interface{}, opts DirOptions) ([]Document, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if isChart(root) {
+		return renderChart(root, root, opts)
+	}
+
+	docs := make([]Document, 0)
+	paths := make([]string, 0)
+	included := map[string]bool{}
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if path != root && isChart(path) {
+				chartDocs, err := renderChart(root, path, opts)
+				if err != nil {
+					return err
+				}
+				for _, doc := range chartDocs {
+					docs = append(docs, doc)
+					paths = append(paths, path)
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		doc, err := renderFile(root, path, bindings, opts, included)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", rel, err)
+		}
+		if doc != nil {
+			doc.Source = strings.TrimSuffix(strings.TrimSuffix(rel, ".liquid"), ".tpl")
+			docs = append(docs, *doc)
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Document, 0, len(docs))
+	for i, doc := range docs {
+		if !included[paths[i]] {
+			res = append(res, doc)
+		}
+	}
+	return res, nil
+}
+
+// Combine joins the documents into a single multi document yaml stream.
+func Combine(docs []Document) []byte {
+	var buf bytes.Buffer
+	for _, doc := range docs {
+		content := bytes.TrimSpace(doc.Content)
+		if len(content) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "---\n# Source: %s\n", doc.Source)
+		content = bytes.TrimPrefix(content, []byte("---\n"))
+		buf.Write(content)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// WriteDir writes every document into dir at its source path.
+func WriteDir(dir string, docs []Document) error {
+	for _, doc := range docs {
+		path := filepath.Join(dir, filepath.FromSlash(doc.Source))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, doc.Content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderFile(root, path string, bindings map[string]interface{}, opts DirOptions, included map[string]bool) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".liquid":
+		return renderLiquid(root, path, content, bindings, included)
+	case ".tpl":
+		out, err := template.RenderTpl(content, bindings)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Content: out}, nil
+	case ".yaml", ".yml", ".json":
+		if opts.Templated {
+			return renderLiquid(root, path, content, bindings, included)
+		}
+		return &Document{Content: content}, nil
+	}
+	return nil, nil
+}
+
+func renderLiquid(root, path string, content []byte, bindings map[string]interface{}, included map[string]bool) (*Document, error) {
+	expanded, err := expandIncludes(root, filepath.Dir(path), content, []string{path}, included)
+	if err != nil {
+		return nil, err
+	}
+	out, err := template.RenderLiquid(expanded, bindings)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Content: out}, nil
+}
+
+// expandIncludes inlines included files before rendering, recording their paths in included. Paths are resolved
+// relative to the including file first and to the root of the folder second, and can't escape the folder.
+func expandIncludes(root, dir string, content []byte, stack []string, included map[string]bool) ([]byte, error) {
+	if len(stack) > maxIncludeDepth {
+		return nil, fmt.Errorf("includes are nested more than %d levels deep", maxIncludeDepth)
+	}
+
+	var err error
+	expanded := includeTag.ReplaceAllFunc(content, func(tag []byte) []byte {
+		if err != nil {
+			return nil
+		}
+		args := string(includeTag.FindSubmatch(tag)[1])
+		match := includePath.FindStringSubmatch(args)
+		if match == nil {
+			err = fmt.Errorf("unsupported include %q, only a quoted path like {%% include \"file.liquid\" %%} can be rendered locally", string(tag))
+			return nil
+		}
+		name := match[1] + match[2]
+
+		var path string
+		if path, err = resolveInclude(root, dir, name); err != nil {
+			return nil
+		}
+		for _, parent := range stack {
+			if parent == path {
+				err = fmt.Errorf("include cycle detected at %s", name)
+				return nil
+			}
+		}
+
+		included[path] = true
+		var content []byte
+		if content, err = os.ReadFile(path); err != nil {
+			return nil
+		}
+		content, err = expandIncludes(root, filepath.Dir(path), content, append(stack, path), included)
+		return content
+	})
+	return expanded, err
+}
+
+func resolveInclude(root, dir, name string) (string, error) {
+	for _, base := range []string{dir, root} {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("include %s is outside of %s", name, root)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("included file %s not found", name)
+}
+
+func isChart(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, chartutil.ChartfileName))
+	return err == nil
+}
+
+func renderChart(root, dir string, opts DirOptions) ([]Document, error) {
+	chart, err := loader.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vals, err := (&values.Options{ValueFiles: opts.ValuesFiles}).MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := templateChart(chart, vals, opts.ReleaseName, opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := filepath.Rel(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	return splitManifest(filepath.ToSlash(prefix), manifest), nil
+}
+
+// templateChart renders a chart client side, like helm template
+func templateChart(chrt *chart.Chart, vals map[string]interface{}, releaseName, namespace string) (string, error) {
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	install.ReleaseName = releaseName
+	if install.ReleaseName == "" {
+		install.ReleaseName = chrt.Name()
+	}
+	install.Namespace = namespace
+	if install.Namespace == "" {
+		install.Namespace = "default"
+	}
+
+	release, err := install.Run(chrt, vals)
+	if err != nil {
+		return "", fmt.Errorf("failed to template chart %s: %w", chrt.Name(), err)
+	}
+	return release.Manifest, nil
+}
+
+// splitManifest breaks a helm manifest into one document per template, sources are relative to the chart's parent
+func splitManifest(prefix, manifest string) []Document {
+	sources := map[string]*bytes.Buffer{}
+	for _, doc := range strings.Split(manifest, "\n---") {
+		match := manifestSource.FindStringSubmatch(doc)
+		if match == nil {
+			continue
+		}
+		// helm prefixes sources with the chart name, which is replaced by the folder
+		source := match[1]
+		if _, rest, ok := strings.Cut(source, "/"); ok {
+			source = rest
+		}
+		if prefix != "." {
+			source = prefix + "/" + source
+		}
+
+		body := strings.TrimSpace(manifestSource.ReplaceAllString(doc, ""))
+		body = strings.TrimSpace(strings.TrimPrefix(body, "---"))
+		if body == "" {
+			continue
+		}
+		if sources[source] == nil {
+			sources[source] = &bytes.Buffer{}
+		} else {
+			sources[source].WriteString("---\n")
+		}
+		sources[source].WriteString(body + "\n")
+	}
+
+	docs := make([]Document, 0, len(sources))
+	for source, buf := range sources {
+		docs = append(docs, Document{Source: source, Content: buf.Bytes()})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Source < docs[j].Source })
+	return docs
+}
@@ -0,0 +1,80 @@
+package template_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/cd/template"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestRenderDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"configmap.yaml.liquid":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ configuration.name }}\ndata:\n{% include \"partials/data.liquid\" %}",
+		"partials/data.liquid":    "  cluster: {{ cluster.handle }}\n{% include \"nested.liquid\" %}",
+		"partials/nested.liquid":  "  nested: \"true\"",
+		"namespace.yaml":          "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: {{ configuration.name }}\n",
+		"README.md":               "ignored",
+		"chart/Chart.yaml":        "apiVersion: v2\nname: example\nversion: 0.1.0\n",
+		"chart/values.yaml":       "replicas: 1\n",
+		"chart/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  replicas: \"{{ .Values.replicas }}\"\n",
+	})
+	values := filepath.Join(t.TempDir(), "values.yaml")
+	require.NoError(t, os.WriteFile(values, []byte("replicas: 3\n"), 0644))
+
+	bindings := map[string]interface{}{
+		"configuration": map[string]interface{}{"name": "app"},
+		"cluster":       map[string]interface{}{"handle": "prod"},
+	}
+	docs, err := template.RenderDir(dir, bindings, template.DirOptions{ValuesFiles: []string{values}, ReleaseName: "app"})
+	require.NoError(t, err)
+
+	sources := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		sources = append(sources, doc.Source)
+	}
+	assert.Equal(t, []string{"chart/templates/cm.yaml", "configmap.yaml", "namespace.yaml"}, sources)
+	assert.Equal(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  replicas: \"3\"\n", string(docs[0].Content))
+	assert.Equal(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  cluster: prod\n  nested: \"true\"", string(docs[1].Content))
+	assert.Contains(t, string(docs[2].Content), "name: {{ configuration.name }}")
+
+	docs, err = template.RenderDir(dir, bindings, template.DirOptions{Templated: true, ReleaseName: "app"})
+	require.NoError(t, err)
+	assert.Contains(t, string(docs[2].Content), "name: app")
+	assert.Contains(t, string(template.Combine(docs)), "---\n# Source: namespace.yaml\napiVersion: v1\nkind: Namespace")
+}
+
+func TestRenderDirIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.liquid": "{% include \"b.liquid\" %}",
+		"b.liquid": "{% include \"a.liquid\" %}",
+	})
+	_, err := template.RenderDir(dir, nil, template.DirOptions{})
+	assert.EqualError(t, err, "failed to render a.liquid: include cycle detected at a.liquid")
+
+	dir = t.TempDir()
+	writeFiles(t, dir, map[string]string{"escape.liquid": "{% include \"../secret\" %}"})
+	_, err = template.RenderDir(dir, nil, template.DirOptions{})
+	assert.ErrorContains(t, err, "include ../secret is outside of")
+
+	for _, tag := range []string{`{% include "data.liquid" with configuration %}`, `{% include configuration.partial %}`} {
+		dir = t.TempDir()
+		writeFiles(t, dir, map[string]string{"main.liquid": tag, "data.liquid": "data: true"})
+		_, err = template.RenderDir(dir, nil, template.DirOptions{})
+		assert.ErrorContains(t, err, "unsupported include", tag)
+	}
+}
@@ -0,0 +1,275 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pluralsh/console/go/polly/template"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"sigs.k8s.io/yaml"
+)
+
+// HelmSource is where the chart of a service comes from, a helm repository, an oci registry or a folder for charts
+// checked into git.
+type HelmSource struct {
+	Chart   string
+	Version string
+	// URL of the helm repository, oci:// urls are pulled from the registry.
+	URL string
+	// Path of a local chart, takes precedence over the repository.
+	Path string
+}
+
+func (s HelmSource) String() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	ref := s.Chart
+	if s.URL != "" {
+		ref = strings.TrimRight(s.URL, "/") + "/" + s.Chart
+	}
+	if s.Version != "" {
+		ref = fmt.Sprintf("%s@%s", ref, s.Version)
+	}
+	return ref
+}
+
+// ValuesLayer is one source of helm values, layers are merged in order so later ones win.
+type ValuesLayer struct {
+	Source string
+	Values map[string]interface{}
+}
+
+// ValueOrigin explains where a final value came from.
+type ValueOrigin struct {
+	Path   string      `json:"path"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	// Overrides lists the earlier sources that set the value as well, in order.
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+type HelmOptions struct {
+	ReleaseName string
+	Namespace   string
+}
+
+type HelmResult struct {
+	Chart     string
+	Documents []Document
+	Values    map[string]interface{}
+	Origins   []ValueOrigin
+	// Sources are the chart defaults and the layers, from lowest to highest precedence.
+	Sources []string
+}
+
+// LoadChart reads a local chart, or pulls it from its repository into a temporary cache.
+func LoadChart(src HelmSource) (*chart.Chart, error) {
+	if src.Path != "" {
+		return loader.Load(src.Path)
+	}
+	if src.Chart == "" && !registry.IsOCI(src.URL) {
+		return nil, fmt.Errorf("no chart to template, set a chart or a local chart folder")
+	}
+
+	cache, err := os.MkdirTemp("", "helm-template-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(cache)
+
+	settings := cli.New()
+	settings.RepositoryCache = cache
+	settings.RepositoryConfig = filepath.Join(cache, "repositories.yaml")
+
+	registryClient, err := registry.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	install := action.NewInstall(&action.Configuration{RegistryClient: registryClient})
+	install.Version = src.Version
+	ref := src.Chart
+	if registry.IsOCI(src.URL) {
+		ref = strings.TrimRight(src.URL, "/")
+		if src.Chart != "" {
+			ref += "/" + strings.TrimLeft(src.Chart, "/")
+		}
+	} else {
+		install.RepoURL = src.URL
+	}
+
+	path, err := install.LocateChart(ref, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull chart %s: %w", src, err)
+	}
+	return loader.Load(path)
+}
+
+// ValuesFile reads a values file as a layer, .liquid files are rendered with the bindings first like the deploy
+// agent does.
+func ValuesFile(path, source string, bindings map[string]interface{}) (ValuesLayer, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ValuesLayer{}, err
+	}
+
+	if strings.HasSuffix(path, ".liquid") {
+		if content, err = template.RenderLiquid(content, bindings); err != nil {
+			return ValuesLayer{}, fmt.Errorf("failed to render values file %s: %w", source, err)
+		}
+	}
+	return ParseValues(source, content)
+}
+
+// ParseValues parses a yaml values document as a layer.
+func ParseValues(source string, content []byte) (ValuesLayer, error) {
+	vals := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &vals); err != nil {
+		return ValuesLayer{}, fmt.Errorf("invalid values in %s: %w", source, err)
+	}
+	return ValuesLayer{Source: source, Values: vals}, nil
+}
+
+// RenderHelm templates the chart with the layers merged in order on top of the chart defaults, and explains where
+// every final value came from.
+func RenderHelm(chrt *chart.Chart, layers []ValuesLayer, opts HelmOptions) (*HelmResult, error) {
+	vals := map[string]interface{}{}
+	for _, layer := range layers {
+		vals = mergeMaps(vals, layer.Values)
+	}
+
+	manifest, err := templateChart(chrt, vals, opts.ReleaseName, opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	final, err := chartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	name := chrt.Name()
+	if chrt.Metadata != nil && chrt.Metadata.Version != "" {
+		name = fmt.Sprintf("%s-%s", name, chrt.Metadata.Version)
+	}
+	all := append([]ValuesLayer{{Source: fmt.Sprintf("chart defaults (%s)", name), Values: chrt.Values}}, layers...)
+	sources := make([]string, 0, len(all))
+	for _, layer := range all {
+		sources = append(sources, layer.Source)
+	}
+
+	return &HelmResult{
+		Chart:     name,
+		Documents: splitManifest(".", manifest),
+		Values:    final.AsMap(),
+		Origins:   Origins(all),
+		Sources:   sources,
+	}, nil
+}
+
+// Origins merges the layers the way helm does and returns the source of every leaf value, sorted by path. Lists
+// are treated as a single value, like helm replaces them rather than merging, and null removes a value.
+func Origins(layers []ValuesLayer) []ValueOrigin {
+	origins := map[string]*ValueOrigin{}
+	for _, layer := range layers {
+		trackLayer(layer.Values, "", layer.Source, origins)
+	}
+
+	res := make([]ValueOrigin, 0, len(origins))
+	for _, origin := range origins {
+		res = append(res, *origin)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
+
+func trackLayer(vals map[string]interface{}, prefix, source string, origins map[string]*ValueOrigin) {
+	for key, value := range vals {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if value == nil {
+			dropOrigins(origins, path)
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			// a map replaces a value set at its path, but merges with the keys below it
+			delete(origins, path)
+			trackLayer(nested, path, source, origins)
+			continue
+		}
+
+		origins[path] = &ValueOrigin{
+			Path:      path,
+			Value:     value,
+			Source:    source,
+			Overrides: dropOrigins(origins, path),
+		}
+	}
+}
+
+// dropOrigins removes the origins at or below path, returning the sources that set them
+func dropOrigins(origins map[string]*ValueOrigin, path string) []string {
+	sources := make([]string, 0)
+	seen := map[string]bool{}
+	add := func(source string) {
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+
+	keys := make([]string, 0)
+	for key := range origins {
+		if key == path || strings.HasPrefix(key, path+".") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, source := range origins[key].Overrides {
+			add(source)
+		}
+		add(origins[key].Source)
+		delete(origins, key)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return sources
+}
+
+// mergeMaps merges b into a like helm merges values files, nested maps are merged and everything else replaced
+func mergeMaps(a, b map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(a))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		if v, ok := v.(map[string]interface{}); ok {
+			if bv, ok := out[k]; ok {
+				if bv, ok := bv.(map[string]interface{}); ok {
+					out[k] = mergeMaps(bv, v)
+					continue
+				}
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
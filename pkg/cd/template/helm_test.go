@@ -0,0 +1,47 @@
+package template_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/cd/template"
+)
+
+func TestRenderHelm(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"chart/Chart.yaml":        "apiVersion: v2\nname: example\nversion: 0.1.0\n",
+		"chart/values.yaml":       "replicas: 1\nimage:\n  repository: nginx\n  tag: latest\nlabels:\n  team: core\n",
+		"chart/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  replicas: \"{{ .Values.replicas }}\"\n  image: \"{{ .Values.image.repository }}:{{ .Values.image.tag }}\"\n",
+		"values/prod.yaml.liquid": "replicas: {{ configuration.replicas }}\nimage:\n  tag: \"1.27\"\n",
+	})
+
+	chart, err := template.LoadChart(template.HelmSource{Path: filepath.Join(dir, "chart")})
+	require.NoError(t, err)
+
+	bindings := map[string]interface{}{"configuration": map[string]interface{}{"replicas": "2"}}
+	prod, err := template.ValuesFile(filepath.Join(dir, "values/prod.yaml.liquid"), "values/prod.yaml.liquid", bindings)
+	require.NoError(t, err)
+	inline, err := template.ParseValues("helm.values", []byte("replicas: 3\nlabels: null\n"))
+	require.NoError(t, err)
+
+	res, err := template.RenderHelm(chart, []template.ValuesLayer{prod, inline}, template.HelmOptions{ReleaseName: "app"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example-0.1.0", res.Chart)
+	assert.Equal(t, []string{"chart defaults (example-0.1.0)", "values/prod.yaml.liquid", "helm.values"}, res.Sources)
+	require.Len(t, res.Documents, 1)
+	assert.Equal(t, "templates/cm.yaml", res.Documents[0].Source)
+	assert.Equal(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  replicas: \"3\"\n  image: \"nginx:1.27\"\n", string(res.Documents[0].Content))
+	assert.NotContains(t, res.Values, "labels")
+
+	defaults := "chart defaults (example-0.1.0)"
+	assert.Equal(t, []template.ValueOrigin{
+		{Path: "image.repository", Value: "nginx", Source: defaults},
+		{Path: "image.tag", Value: "1.27", Source: "values/prod.yaml.liquid", Overrides: []string{defaults}},
+		{Path: "replicas", Value: float64(3), Source: "helm.values", Overrides: []string{defaults, "values/prod.yaml.liquid"}},
+	}, res.Origins)
+}
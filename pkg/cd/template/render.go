@@ -27,15 +27,15 @@ func RenderYaml(path string, bindings map[string]interface{}) ([]byte, error) {
 }
 
 func RenderService(path string, svc *console.ServiceDeploymentExtended) ([]byte, error) {
-	bindings := map[string]interface{}{
-		"Configuration": configMap(svc),
-		"Cluster":       clusterConfiguration(svc.Cluster),
-		"Contexts":      contexts(svc),
-	}
+	return RenderYaml(path, ServiceBindings(svc))
+}
 
-	for k, v := range bindings {
-		bindings[strings.ToLower(k)] = v
-	}
+// ServiceBindings are the template bindings the deploy agent uses for a service.
+func ServiceBindings(svc *console.ServiceDeploymentExtended) map[string]interface{} {
+	return NewSnapshot(svc, false).TemplateBindings()
+}
 
-	return RenderYaml(path, bindings)
+// ServiceLuaBindings are the globals the deploy agent runs the lua script of a service with.
+func ServiceLuaBindings(svc *console.ServiceDeploymentExtended) map[string]interface{} {
+	return NewSnapshot(svc, false).LuaBindings()
 }
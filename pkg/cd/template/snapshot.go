@@ -0,0 +1,222 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	console "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"sigs.k8s.io/yaml"
+)
+
+// Redacted replaces secret values in snapshots.
+const Redacted = "<redacted>"
+
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private[-_]?key|api[-_]?key|access[-_]?key)`)
+
+// Snapshot is an offline copy of the service data templates and lua scripts are rendered with, so they can be
+// rendered without access to the console.
+type Snapshot struct {
+	Service       SnapshotService                   `json:"service"`
+	Configuration map[string]string                 `json:"configuration,omitempty"`
+	Cluster       SnapshotCluster                   `json:"cluster"`
+	Contexts      map[string]map[string]interface{} `json:"contexts,omitempty"`
+	Imports       map[string]map[string]string      `json:"imports,omitempty"`
+}
+
+type SnapshotService struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// SnapshotCluster keeps the optional cluster fields as pointers, so fields the console didn't set stay nil in the
+// bindings like they are when rendering against the live service.
+type SnapshotCluster struct {
+	ID             string                 `json:"id"`
+	Self           *bool                  `json:"self,omitempty"`
+	Handle         *string                `json:"handle,omitempty"`
+	Name           string                 `json:"name"`
+	Version        *string                `json:"version,omitempty"`
+	CurrentVersion *string                `json:"currentVersion,omitempty"`
+	KasURL         *string                `json:"kasUrl,omitempty"`
+	Distro         *console.ClusterDistro `json:"distro,omitempty"`
+	Tags           map[string]string      `json:"tags,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// NewSnapshot copies the data a service is rendered with, configuration, contexts and secret stack outputs are
+// redacted unless redact is false.
+func NewSnapshot(svc *console.ServiceDeploymentExtended, redact bool) *Snapshot {
+	snapshot := &Snapshot{
+		Service:       SnapshotService{Name: svc.Name, Namespace: svc.Namespace},
+		Configuration: configMap(svc),
+		Contexts:      contexts(svc),
+		Imports:       map[string]map[string]string{},
+	}
+
+	if cluster := svc.Cluster; cluster != nil {
+		snapshot.Cluster = SnapshotCluster{
+			ID:             cluster.ID,
+			Self:           cluster.Self,
+			Handle:         cluster.Handle,
+			Name:           cluster.Name,
+			Version:        cluster.Version,
+			CurrentVersion: cluster.CurrentVersion,
+			KasURL:         cluster.KasURL,
+			Distro:         cluster.Distro,
+			Tags:           map[string]string{},
+			Metadata:       cluster.Metadata,
+		}
+		for _, tag := range cluster.Tags {
+			snapshot.Cluster.Tags[tag.Name] = tag.Value
+		}
+	}
+
+	for _, imp := range svc.Imports {
+		outputs := map[string]string{}
+		for _, out := range imp.Outputs {
+			outputs[out.Name] = out.Value
+			if redact && lo.FromPtr(out.Secret) {
+				outputs[out.Name] = Redacted
+			}
+		}
+		snapshot.Imports[imp.Stack.Name] = outputs
+	}
+
+	if redact {
+		snapshot.Redact()
+	}
+	return snapshot
+}
+
+// LoadSnapshot reads a snapshot written by `plural cd services snapshot`.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := yaml.UnmarshalStrict(data, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid bindings file %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// Redact replaces every service configuration and context value, and every cluster metadata value with a
+// sensitive looking key. Configuration is stored encrypted by the console and contexts commonly hold credentials,
+// so all of them are treated as secret, while nested keys are kept so templates still find them.
+func (s *Snapshot) Redact() {
+	for key := range s.Configuration {
+		s.Configuration[key] = Redacted
+	}
+	for _, ctx := range s.Contexts {
+		redactAll(ctx)
+	}
+	redactSensitive(s.Cluster.Metadata)
+}
+
+func redactAll(values map[string]interface{}) {
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			redactAll(nested)
+			continue
+		}
+		values[key] = Redacted
+	}
+}
+
+func redactSensitive(values map[string]interface{}) {
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			redactSensitive(nested)
+			continue
+		}
+		if sensitiveKey.MatchString(key) {
+			values[key] = Redacted
+		}
+	}
+}
+
+// TemplateBindings returns the bindings .liquid and .tpl files are rendered with.
+func (s *Snapshot) TemplateBindings() map[string]interface{} {
+	bindings := map[string]interface{}{
+		"Configuration": s.configuration(),
+		"Cluster":       s.cluster(false),
+		"Contexts":      s.contexts(),
+	}
+	for k, v := range bindings {
+		bindings[strings.ToLower(k)] = v
+	}
+	return bindings
+}
+
+// LuaBindings returns the globals lua scripts are executed with.
+func (s *Snapshot) LuaBindings() map[string]interface{} {
+	service := map[string]interface{}{
+		"Name":      s.Service.Name,
+		"Namespace": s.Service.Namespace,
+	}
+	for k, v := range service {
+		service[strings.ToLower(k)] = v
+	}
+
+	imports := s.Imports
+	if imports == nil {
+		imports = map[string]map[string]string{}
+	}
+
+	return map[string]interface{}{
+		"configuration": s.configuration(),
+		"cluster":       s.cluster(true),
+		"contexts":      s.contexts(),
+		"imports":       imports,
+		"service":       service,
+	}
+}
+
+func (s *Snapshot) configuration() map[string]string {
+	if s.Configuration == nil {
+		return map[string]string{}
+	}
+	return s.Configuration
+}
+
+func (s *Snapshot) contexts() map[string]map[string]interface{} {
+	if s.Contexts == nil {
+		return map[string]map[string]interface{}{}
+	}
+	return s.Contexts
+}
+
+// cluster builds the cluster binding of templates and lua scripts, lua scripts also see the tags and distro
+func (s *Snapshot) cluster(lua bool) map[string]interface{} {
+	c := s.Cluster
+	res := map[string]interface{}{
+		"ID":             c.ID,
+		"Self":           c.Self,
+		"Handle":         c.Handle,
+		"Name":           c.Name,
+		"Version":        c.Version,
+		"CurrentVersion": c.CurrentVersion,
+		"KasUrl":         c.KasURL,
+		"Metadata":       c.Metadata,
+	}
+	if lua {
+		tags := c.Tags
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		res["Tags"] = tags
+		res["Distro"] = c.Distro
+	}
+
+	for k, v := range res {
+		res[strings.ToLower(k)] = v
+	}
+	res["kasUrl"] = c.KasURL
+	res["currentVersion"] = c.CurrentVersion
+	return res
+}
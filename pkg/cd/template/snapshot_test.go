@@ -0,0 +1,78 @@
+package template_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	console "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/pluralsh/plural-cli/pkg/cd/template"
+)
+
+const snapshotFixture = `service:
+  name: payments
+  namespace: apps
+configuration:
+  env: production
+cluster:
+  id: cluster-1
+  handle: prod
+  name: production
+  distro: EKS
+  tags:
+    env: prod
+  metadata:
+    team: platform
+    auth:
+      clientSecret: abc
+contexts:
+  db:
+    host: db.internal
+    password: hunter2
+imports:
+  network:
+    vpc_id: vpc-123
+`
+
+func TestSnapshotBindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(snapshotFixture), 0644))
+
+	snapshot, err := template.LoadSnapshot(path)
+	require.NoError(t, err)
+
+	bindings := snapshot.TemplateBindings()
+	assert.Equal(t, map[string]string{"env": "production"}, bindings["Configuration"])
+	assert.Equal(t, bindings["Configuration"], bindings["configuration"])
+	cluster := bindings["cluster"].(map[string]interface{})
+	assert.Equal(t, lo.ToPtr("prod"), cluster["handle"])
+	assert.Equal(t, lo.ToPtr("prod"), cluster["Handle"])
+	assert.Nil(t, cluster["version"])
+	assert.Nil(t, cluster["self"])
+	assert.NotContains(t, cluster, "tags")
+
+	lua := snapshot.LuaBindings()
+	assert.Equal(t, map[string]string{"env": "prod"}, lua["cluster"].(map[string]interface{})["tags"])
+	assert.Equal(t, lo.ToPtr(console.ClusterDistro("EKS")), lua["cluster"].(map[string]interface{})["distro"])
+	assert.Equal(t, map[string]map[string]string{"network": {"vpc_id": "vpc-123"}}, lua["imports"])
+	assert.Equal(t, map[string]interface{}{"Name": "payments", "name": "payments", "Namespace": "apps", "namespace": "apps"}, lua["service"])
+
+	snapshot.Redact()
+	out, err := yaml.Marshal(snapshot)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "env: <redacted>")
+	assert.Contains(t, string(out), "password: <redacted>")
+	assert.Contains(t, string(out), "clientSecret: <redacted>")
+	assert.Contains(t, string(out), "host: <redacted>")
+	assert.Contains(t, string(out), "team: platform")
+	assert.NotContains(t, string(out), "version:")
+
+	require.NoError(t, os.WriteFile(path, []byte("service:\n  nme: typo\n"), 0644))
+	_, err = template.LoadSnapshot(path)
+	assert.ErrorContains(t, err, `unknown field "nme"`)
+}
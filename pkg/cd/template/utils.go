@@ -1,32 +1,9 @@
 package template
 
 import (
-	"strings"
-
 	console "github.com/pluralsh/console/go/client"
 )
 
-func clusterConfiguration(cluster *console.BaseClusterFragment) map[string]interface{} {
-	res := map[string]interface{}{
-		"ID":             cluster.ID,
-		"Self":           cluster.Self,
-		"Handle":         cluster.Handle,
-		"Name":           cluster.Name,
-		"Version":        cluster.Version,
-		"CurrentVersion": cluster.CurrentVersion,
-		"KasUrl":         cluster.KasURL,
-		"Metadata":       cluster.Metadata,
-	}
-
-	for k, v := range res {
-		res[strings.ToLower(k)] = v
-	}
-	res["kasUrl"] = cluster.KasURL
-	res["currentVersion"] = cluster.CurrentVersion
-
-	return res
-}
-
 func configMap(svc *console.ServiceDeploymentExtended) map[string]string {
 	res := map[string]string{}
 	for _, config := range svc.Configuration {
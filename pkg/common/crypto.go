@@ -55,8 +55,8 @@ func EnsureGitIgnore() error {
 
 func CryptoInit(_ *cli.Context) error {
 	encryptConfig := [][]string{
-		{"filter.plural-crypt.smudge", "plural crypto decrypt"},
-		{"filter.plural-crypt.clean", "plural crypto encrypt"},
+		{"filter.plural-crypt.smudge", "plural crypto decrypt --path %f"},
+		{"filter.plural-crypt.clean", "plural crypto encrypt --path %f"},
 		{"filter.plural-crypt.required", "true"},
 		{"diff.plural-crypt.textconv", "plural crypto decrypt"},
 	}
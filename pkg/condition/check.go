@@ -0,0 +1,173 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindAny    Kind = "any"
+	KindString Kind = "string"
+	KindInt    Kind = "int"
+	KindBool   Kind = "bool"
+	KindList   Kind = "list"
+)
+
+// Field describes a value an expression may reference, Values restricts the strings of enumerations.
+type Field struct {
+	Kind   Kind
+	Values []string
+}
+
+// Check type checks the expression against the fields it may reference, returning every problem found.
+func (e *Expression) Check(fields map[string]Field) []error {
+	if e.root == nil {
+		return nil
+	}
+
+	c := &checker{fields: fields}
+	c.node(e.root)
+	for i, err := range c.errs {
+		c.errs[i] = fmt.Errorf("condition %q: %w", e.source, err)
+	}
+	return c.errs
+}
+
+type checker struct {
+	fields map[string]Field
+	errs   []error
+}
+
+func (c *checker) errorf(format string, args ...any) {
+	c.errs = append(c.errs, fmt.Errorf(format, args...))
+}
+
+func (c *checker) node(n node) {
+	switch n := n.(type) {
+	case *logical:
+		c.node(n.left)
+		c.node(n.right)
+	case *negation:
+		c.node(n.inner)
+	case *truthy:
+		c.operand(n.operand)
+	case *emptiness:
+		c.operand(n.operand)
+	case *comparison:
+		c.comparison(n)
+	}
+}
+
+// operand returns the field an operand reads, which is unrestricted for literals
+func (c *checker) operand(o operand) Field {
+	switch o := o.(type) {
+	case *selector:
+		if len(o.path) == 0 || o.path[0] == "" {
+			c.errorf("%s does not name a field", o)
+			return Field{Kind: KindAny}
+		}
+		field, ok := c.fields[o.path[0]]
+		if !ok {
+			c.errorf("%s references unknown field %s", o, o.path[0])
+			return Field{Kind: KindAny}
+		}
+		if len(o.path) > 1 {
+			return Field{Kind: KindAny}
+		}
+		return field
+	case *list:
+		for _, item := range o.items {
+			c.operand(item)
+		}
+		return Field{Kind: KindList}
+	case *literal:
+		switch o.value.(type) {
+		case float64:
+			return Field{Kind: KindInt}
+		case bool:
+			return Field{Kind: KindBool}
+		}
+		return Field{Kind: KindString}
+	}
+	return Field{Kind: KindAny}
+}
+
+func (c *checker) comparison(cmp *comparison) {
+	left, right := c.operand(cmp.left), c.operand(cmp.right)
+	switch cmp.op {
+	case "==", "!=":
+		c.comparable(cmp.left, left, cmp.right)
+		c.comparable(cmp.right, right, cmp.left)
+	case "<", "<=", ">", ">=":
+		for _, side := range []struct {
+			operand operand
+			field   Field
+		}{{cmp.left, left}, {cmp.right, right}} {
+			if !numeric(side.operand, side.field) {
+				c.errorf("%s can't be ordered with %s, it must be a number", side.operand, cmp.op)
+			}
+		}
+	case "in":
+		c.container(cmp.right, right)
+		if l, ok := cmp.right.(*list); ok {
+			for _, item := range l.items {
+				c.comparable(cmp.left, left, item)
+			}
+		}
+	case "contains":
+		c.container(cmp.left, left)
+	case "matches":
+		if left.Kind == KindBool || left.Kind == KindList {
+			c.errorf("%s can't be matched against a regular expression, it is not a string", cmp.left)
+		}
+	}
+}
+
+// comparable checks that a literal compared against a typed field can be coerced to its type
+func (c *checker) comparable(typed operand, field Field, other operand) {
+	if _, ok := typed.(*selector); !ok {
+		return
+	}
+	lit, ok := other.(*literal)
+	if !ok {
+		return
+	}
+
+	switch field.Kind {
+	case KindInt:
+		if _, ok := toNumber(lit.value); !ok {
+			c.errorf("%s is an int and can't equal %s", typed, lit)
+		}
+	case KindBool:
+		if _, ok := toBool(lit.value); !ok {
+			c.errorf("%s is a bool and can't equal %s", typed, lit)
+		}
+	case KindString:
+		if len(field.Values) == 0 {
+			return
+		}
+		value := toString(lit.value)
+		for _, allowed := range field.Values {
+			if value == allowed {
+				return
+			}
+		}
+		c.errorf("%s is never %s, it must be one of %s", typed, lit, strings.Join(field.Values, ", "))
+	}
+}
+
+func (c *checker) container(o operand, field Field) {
+	if field.Kind == KindInt || field.Kind == KindBool {
+		c.errorf("%s is not a list or string", o)
+	}
+}
+
+func numeric(o operand, field Field) bool {
+	if lit, ok := o.(*literal); ok {
+		_, isNumber := toNumber(lit.value)
+		return isNumber
+	}
+	return field.Kind == KindInt || field.Kind == KindAny
+}
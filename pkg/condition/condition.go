@@ -0,0 +1,136 @@
+// Package condition implements the expressions that enable configuration items and create templates.
+//
+// Expressions combine comparisons with and, or and not (also written &&, || and !), and support
+//
+//	context.type == "s3"                 equality, also != and the orderings < <= > >=
+//	context.region in ["us-east-1", eu]  membership in a list, or of a substring in a string
+//	context.tags contains "prod"         the reverse of in
+//	context.name matches "^[a-z]+$"      regular expression match
+//	context.name is not empty            emptiness checks
+//	context.replicas > context.minimum   references to other fields on either side
+//
+// Selectors start with context. Unquoted words are string values, so expressions written for go-bexpr
+// keep working. Values are coerced for comparisons: numbers and booleans given as strings compare as
+// numbers and booleans, and missing fields compare as the empty string.
+//
+// go-bexpr is not used because it has no ordering comparisons, only accepts literals on the right hand
+// side and compares configuration strings without coercion, and pr lint needs the parsed expression to
+// type check it against the configuration items.
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SelectorRoot prefixes every field reference.
+const SelectorRoot = "context"
+
+// Expression is a parsed condition.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses an expression, an empty expression always holds.
+func Parse(expression string) (*Expression, error) {
+	if strings.TrimSpace(expression) == "" {
+		return &Expression{}, nil
+	}
+
+	tokens, err := lex(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expression, err)
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEOF {
+		err = p.errorf(p.peek(), "unexpected token")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expression, err)
+	}
+	return &Expression{source: expression, root: root}, nil
+}
+
+// FromCondition converts the structured field, operation and value conditions of configuration items.
+// Besides the console operations NOT, EQ, GT, GTE, LT, LTE, PREFIX and SUFFIX it accepts NEQ, IN with a
+// comma separated list of values, MATCHES with a regular expression and EXPR with a full expression.
+func FromCondition(field, operation, value string) (*Expression, error) {
+	sel := newSelector(SelectorRoot + "." + field)
+	source := fmt.Sprintf("%s %s %q", field, operation, value)
+
+	var root node
+	switch strings.ToUpper(operation) {
+	case "NOT":
+		root = &unsetOrFalse{operand: sel}
+		source = "not " + field
+	case "EQ":
+		root = &comparison{op: "==", left: sel, right: &literal{value: value}}
+	case "NEQ":
+		root = &comparison{op: "!=", left: sel, right: &literal{value: value}}
+	case "GT", "GTE", "LT", "LTE":
+		ops := map[string]string{"GT": ">", "GTE": ">=", "LT": "<", "LTE": "<="}
+		root = &comparison{op: ops[strings.ToUpper(operation)], left: sel, right: &literal{value: value}}
+	case "PREFIX":
+		root = &comparison{op: "matches", left: sel, right: &literal{value: value}, regex: regexp.MustCompile("^" + regexp.QuoteMeta(value))}
+	case "SUFFIX":
+		root = &comparison{op: "matches", left: sel, right: &literal{value: value}, regex: regexp.MustCompile(regexp.QuoteMeta(value) + "$")}
+	case "MATCHES":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q in condition on %s: %w", value, field, err)
+		}
+		root = &comparison{op: "matches", left: sel, right: &literal{value: value}, regex: re}
+	case "IN":
+		items := make([]operand, 0)
+		for _, item := range strings.Split(value, ",") {
+			items = append(items, &literal{value: strings.TrimSpace(item)})
+		}
+		root = &comparison{op: "in", left: sel, right: &list{items: items}}
+	case "EXPR":
+		return Parse(value)
+	default:
+		return nil, fmt.Errorf("unsupported operation %q in condition on %s", operation, field)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate evaluates the expression against the context values, errors are returned for comparisons
+// that can't be made rather than silently failing them.
+func (e *Expression) Evaluate(ctx map[string]interface{}) (bool, error) {
+	if e.root == nil {
+		return true, nil
+	}
+
+	result, err := e.root.evaluate(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", e.source, err)
+	}
+	return result, nil
+}
+
+// References returns the fields the expression reads, without the context prefix.
+func (e *Expression) References() []string {
+	if e.root == nil {
+		return nil
+	}
+
+	refs := make([]string, 0)
+	seen := map[string]bool{}
+	e.root.walk(func(o operand) {
+		if sel, ok := o.(*selector); ok && len(sel.path) > 0 && !seen[sel.path[0]] {
+			seen[sel.path[0]] = true
+			refs = append(refs, sel.path[0])
+		}
+	})
+	return refs
+}
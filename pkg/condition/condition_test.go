@@ -0,0 +1,183 @@
+package condition_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/condition"
+)
+
+func TestEvaluate(t *testing.T) {
+	ctx := map[string]interface{}{
+		"type":     "s3",
+		"name":     "blob-store",
+		"replicas": 3,
+		"minimum":  "2",
+		"ha":       "true",
+		"tags":     []interface{}{"prod", "eu"},
+		"nested":   map[string]interface{}{"enabled": true},
+	}
+
+	tests := []struct {
+		expression    string
+		expected      bool
+		expectedError string
+	}{
+		{expression: "", expected: true},
+		{expression: `context.type == "s3"`, expected: true},
+		{expression: `context.type == s3`, expected: true},
+		{expression: `context.type != "s3"`, expected: false},
+		{expression: `context.replicas == "3"`, expected: true},
+		{expression: `context.replicas > context.minimum`, expected: true},
+		{expression: `context.replicas >= 4 or context.ha == true`, expected: true},
+		{expression: `context.replicas >= 4 || context.missing`, expected: false},
+		{expression: `context.type in ["s3", "gcs"] and not (context.name matches "^prod-")`, expected: true},
+		{expression: `context.type not in [gcs, azure]`, expected: true},
+		{expression: `"eu" in context.tags && context.tags contains "prod"`, expected: true},
+		{expression: `"store" in context.name`, expected: true},
+		{expression: `context.nested.enabled`, expected: true},
+		{expression: `!context.nested.enabled`, expected: false},
+		{expression: `context.missing is empty and context.name is not empty`, expected: true},
+		{expression: `context.missing == ""`, expected: true},
+		{expression: `context.missing > 2`, expected: false},
+		{
+			expression:    `context.name > 2`,
+			expectedError: `failed to evaluate condition "context.name > 2": cannot compare context.name (blob-store) > 2 (2), both sides must be numbers`,
+		},
+		{
+			expression: `context.type contains "s"`,
+			expected:   true,
+		},
+		{
+			expression:    `context.replicas contains 3`,
+			expectedError: `failed to evaluate condition "context.replicas contains 3": context.replicas contains 3: 3 is not a list, map or string`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			expr, err := condition.Parse(test.expression)
+			require.NoError(t, err)
+
+			result, err := expr.Evaluate(ctx)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expression    string
+		expectedError string
+	}{
+		{expression: `context.type ==`, expectedError: `invalid condition "context.type ==": expected a value or selector at position 15, found "end of expression"`},
+		{expression: `context.type == "s3`, expectedError: `invalid condition "context.type == \"s3": unterminated string starting at position 16`},
+		{expression: `context.name matches "["`, expectedError: "invalid condition \"context.name matches \\\"[\\\"\": invalid regular expression \"[\": error parsing regexp: missing closing ]: `[`"},
+		{expression: `(context.a == 1`, expectedError: `invalid condition "(context.a == 1": expected ")" at position 15, found "end of expression"`},
+		{expression: `context.a == 1 context.b`, expectedError: `invalid condition "context.a == 1 context.b": unexpected token at position 15, found "context.b"`},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := condition.Parse(test.expression)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestFromCondition(t *testing.T) {
+	ctx := map[string]interface{}{"replicas": 3, "name": "test-prefix", "enabled": false, "flag": "false", "zero": 0}
+
+	tests := []struct {
+		field, operation, value string
+		expected                bool
+	}{
+		{field: "replicas", operation: "EQ", value: "3", expected: true},
+		{field: "replicas", operation: "GT", value: "2", expected: true},
+		{field: "replicas", operation: "LTE", value: "2", expected: false},
+		{field: "name", operation: "PREFIX", value: "test", expected: true},
+		{field: "name", operation: "SUFFIX", value: "test", expected: false},
+		{field: "name", operation: "IN", value: "a, test-prefix", expected: true},
+		{field: "name", operation: "MATCHES", value: "^test-.*$", expected: true},
+		{field: "enabled", operation: "NOT", expected: true},
+		{field: "missing", operation: "NOT", expected: true},
+		{field: "flag", operation: "NOT", expected: false},
+		{field: "zero", operation: "NOT", expected: false},
+		{field: "missing", operation: "GT", value: "1", expected: false},
+		{operation: "EXPR", value: `context.replicas > 2 and not context.enabled`, expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.field+" "+test.operation, func(t *testing.T) {
+			expr, err := condition.FromCondition(test.field, test.operation, test.value)
+			require.NoError(t, err)
+
+			result, err := expr.Evaluate(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	_, err := condition.FromCondition("name", "BETWEEN", "1")
+	assert.EqualError(t, err, `unsupported operation "BETWEEN" in condition on name`)
+}
+
+func TestCheck(t *testing.T) {
+	fields := map[string]condition.Field{
+		"replicas": {Kind: condition.KindInt},
+		"ha":       {Kind: condition.KindBool},
+		"type":     {Kind: condition.KindString, Values: []string{"s3", "gcs"}},
+		"name":     {Kind: condition.KindString},
+	}
+
+	tests := []struct {
+		expression     string
+		expectedErrors []string
+	}{
+		{expression: `context.replicas > 2 and context.type in [s3, gcs] and context.ha == "true"`},
+		{
+			expression: `context.replicas == "three" or context.ha == "yes"`,
+			expectedErrors: []string{
+				`condition "context.replicas == \"three\" or context.ha == \"yes\"": context.replicas is an int and can't equal "three"`,
+				`condition "context.replicas == \"three\" or context.ha == \"yes\"": context.ha is a bool and can't equal "yes"`,
+			},
+		},
+		{
+			expression:     `context.type == "azure"`,
+			expectedErrors: []string{`condition "context.type == \"azure\"": context.type is never "azure", it must be one of s3, gcs`},
+		},
+		{
+			expression:     `context.name > 2`,
+			expectedErrors: []string{`condition "context.name > 2": context.name can't be ordered with >, it must be a number`},
+		},
+		{
+			expression:     `context.region == "eu"`,
+			expectedErrors: []string{`condition "context.region == \"eu\"": context.region references unknown field region`},
+		},
+		{
+			expression:     `context.replicas contains 1`,
+			expectedErrors: []string{`condition "context.replicas contains 1": context.replicas is not a list or string`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			expr, err := condition.Parse(test.expression)
+			require.NoError(t, err)
+
+			errs := expr.Check(fields)
+			messages := make([]string, 0, len(errs))
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			if len(test.expectedErrors) == 0 {
+				assert.Empty(t, messages)
+				return
+			}
+			assert.Equal(t, test.expectedErrors, messages)
+		})
+	}
+}
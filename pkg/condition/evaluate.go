@@ -0,0 +1,321 @@
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	evaluate(ctx map[string]interface{}) (bool, error)
+	walk(fn func(operand))
+}
+
+type operand interface {
+	resolve(ctx map[string]interface{}) interface{}
+	String() string
+}
+
+type logical struct {
+	or          bool
+	left, right node
+}
+
+func (l *logical) evaluate(ctx map[string]interface{}) (bool, error) {
+	left, err := l.left.evaluate(ctx)
+	if err != nil || left == l.or {
+		return left, err
+	}
+	return l.right.evaluate(ctx)
+}
+
+func (l *logical) walk(fn func(operand)) {
+	l.left.walk(fn)
+	l.right.walk(fn)
+}
+
+type negation struct {
+	inner node
+}
+
+func (n *negation) evaluate(ctx map[string]interface{}) (bool, error) {
+	result, err := n.inner.evaluate(ctx)
+	return !result, err
+}
+
+func (n *negation) walk(fn func(operand)) {
+	n.inner.walk(fn)
+}
+
+// truthy holds for true booleans, non-zero numbers and non-empty values other than "false"
+type truthy struct {
+	operand operand
+}
+
+func (t *truthy) evaluate(ctx map[string]interface{}) (bool, error) {
+	switch v := t.operand.resolve(ctx).(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+		return v != "", nil
+	default:
+		if f, ok := toNumber(v); ok {
+			return f != 0, nil
+		}
+		return !isEmpty(v), nil
+	}
+}
+
+func (t *truthy) walk(fn func(operand)) {
+	fn(t.operand)
+}
+
+// unsetOrFalse holds for missing fields and false booleans only, the structured NOT condition of configuration
+// items has always had these semantics, unlike not in expressions
+type unsetOrFalse struct {
+	operand operand
+}
+
+func (u *unsetOrFalse) evaluate(ctx map[string]interface{}) (bool, error) {
+	switch v := u.operand.resolve(ctx).(type) {
+	case nil:
+		return true, nil
+	case bool:
+		return !v, nil
+	default:
+		return false, nil
+	}
+}
+
+func (u *unsetOrFalse) walk(fn func(operand)) {
+	fn(u.operand)
+}
+
+type emptiness struct {
+	operand operand
+	negate  bool
+}
+
+func (e *emptiness) evaluate(ctx map[string]interface{}) (bool, error) {
+	return isEmpty(e.operand.resolve(ctx)) != e.negate, nil
+}
+
+func (e *emptiness) walk(fn func(operand)) {
+	fn(e.operand)
+}
+
+type comparison struct {
+	op          string
+	left, right operand
+	negate      bool
+	regex       *regexp.Regexp
+}
+
+func (c *comparison) evaluate(ctx map[string]interface{}) (bool, error) {
+	left, right := c.left.resolve(ctx), c.right.resolve(ctx)
+
+	var result bool
+	switch c.op {
+	case "==":
+		result = equal(left, right)
+	case "!=":
+		result = !equal(left, right)
+	case "<", "<=", ">", ">=":
+		// a missing field can't be ordered, which never enables anything
+		if left == nil || right == nil {
+			return false, nil
+		}
+		l, lok := toNumber(left)
+		r, rok := toNumber(right)
+		if !lok || !rok {
+			return false, fmt.Errorf("cannot compare %s (%v) %s %s (%v), both sides must be numbers", c.left, left, c.op, c.right, right)
+		}
+		result = map[string]bool{"<": l < r, "<=": l <= r, ">": l > r, ">=": l >= r}[c.op]
+	case "in":
+		found, err := contains(right, left)
+		if err != nil {
+			return false, fmt.Errorf("%s in %s: %w", c.left, c.right, err)
+		}
+		result = found
+	case "contains":
+		found, err := contains(left, right)
+		if err != nil {
+			return false, fmt.Errorf("%s contains %s: %w", c.left, c.right, err)
+		}
+		result = found
+	case "matches":
+		result = c.regex.MatchString(toString(left))
+	}
+	return result != c.negate, nil
+}
+
+func (c *comparison) walk(fn func(operand)) {
+	fn(c.left)
+	fn(c.right)
+}
+
+type literal struct {
+	value interface{}
+	// bare is set for unquoted words
+	bare bool
+}
+
+func (l *literal) resolve(map[string]interface{}) interface{} {
+	return l.value
+}
+
+func (l *literal) String() string {
+	if s, ok := l.value.(string); ok && !l.bare {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(l.value)
+}
+
+type selector struct {
+	source string
+	path   []string
+}
+
+func newSelector(source string) *selector {
+	path := strings.Split(source, ".")[1:]
+	return &selector{source: source, path: path}
+}
+
+func (s *selector) resolve(ctx map[string]interface{}) interface{} {
+	var value interface{} = ctx
+	for _, name := range s.path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[name]
+	}
+	return value
+}
+
+func (s *selector) String() string {
+	return s.source
+}
+
+type list struct {
+	items []operand
+}
+
+func (l *list) resolve(ctx map[string]interface{}) interface{} {
+	values := make([]interface{}, 0, len(l.items))
+	for _, item := range l.items {
+		values = append(values, item.resolve(ctx))
+	}
+	return values
+}
+
+func (l *list) String() string {
+	items := make([]string, 0, len(l.items))
+	for _, item := range l.items {
+		items = append(items, item.String())
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// equal compares numerically or as booleans when both values can be read that way, and as strings otherwise
+func equal(left, right interface{}) bool {
+	if l, ok := toNumber(left); ok {
+		if r, ok := toNumber(right); ok {
+			return l == r
+		}
+	}
+	if l, ok := toBool(left); ok {
+		if r, ok := toBool(right); ok {
+			return l == r
+		}
+	}
+	return toString(left) == toString(right)
+}
+
+func contains(container, item interface{}) (bool, error) {
+	switch c := container.(type) {
+	case nil:
+		return false, nil
+	case string:
+		return strings.Contains(c, toString(item)), nil
+	case map[string]interface{}:
+		_, ok := c[toString(item)]
+		return ok, nil
+	}
+
+	value := reflect.ValueOf(container)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return false, fmt.Errorf("%v is not a list, map or string", container)
+	}
+	for i := 0; i < value.Len(); i++ {
+		if equal(value.Index(i).Interface(), item) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func lex(input string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'' || r == '`':
+			value, next, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = next
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_-.", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), pos: start})
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func lexString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == quote:
+			return sb.String(), i + 1, nil
+		case runes[i] == '\\' && quote != '`' && i+1 < len(runes):
+			i++
+			sb.WriteRune(runes[i])
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string starting at position %d", start)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether the next token is one of the given identifiers or operators, consuming it if so
+func (p *parser) keyword(words ...string) bool {
+	t := p.peek()
+	if t.kind != tokenIdent && t.kind != tokenOperator {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(t.value, word) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(value string) error {
+	if t := p.next(); t.kind != tokenOperator || t.value != value {
+		return p.errorf(t, "expected %q", value)
+	}
+	return nil
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	found := t.value
+	if t.kind == tokenEOF {
+		found = "end of expression"
+	}
+	return fmt.Errorf("%s at position %d, found %q", fmt.Sprintf(format, args...), t.pos, found)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and", "&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.keyword("not", "!") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &negation{inner: inner}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	if t := p.peek(); t.kind == tokenOperator && t.value == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.keyword("is") {
+		negate := p.keyword("not")
+		if !p.keyword("empty") {
+			return nil, p.errorf(p.peek(), "expected \"empty\"")
+		}
+		return &emptiness{operand: left, negate: negate}, nil
+	}
+
+	negate := false
+	if t := p.peek(); t.kind == tokenIdent && strings.EqualFold(t.value, "not") {
+		p.next()
+		negate = true
+	}
+
+	t := p.peek()
+	op := strings.ToLower(t.value)
+	switch {
+	case t.kind == tokenOperator && (op == "==" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">=") && !negate,
+		t.kind == tokenIdent && (op == "in" || op == "contains" || op == "matches"):
+		p.next()
+	default:
+		if negate {
+			return nil, p.errorf(t, "expected \"in\", \"contains\" or \"matches\" after \"not\"")
+		}
+		return &truthy{operand: left}, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := &comparison{op: op, left: left, right: right, negate: negate}
+	if op == "matches" {
+		var pattern string
+		lit, ok := right.(*literal)
+		if ok {
+			pattern, ok = lit.value.(string)
+		}
+		if !ok {
+			return nil, fmt.Errorf("matches expects a quoted regular expression at position %d", t.pos)
+		}
+		if cmp.regex, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literal{value: t.value}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number")
+		}
+		return &literal{value: value}, nil
+	case tokenIdent:
+		switch {
+		case strings.EqualFold(t.value, "true"):
+			return &literal{value: true}, nil
+		case strings.EqualFold(t.value, "false"):
+			return &literal{value: false}, nil
+		case t.value == SelectorRoot || strings.HasPrefix(t.value, SelectorRoot+"."):
+			return newSelector(t.value), nil
+		}
+		// unquoted words are string values, the way go-bexpr treated them
+		return &literal{value: t.value, bare: true}, nil
+	case tokenOperator:
+		if t.value == "[" {
+			return p.parseList()
+		}
+	}
+	return nil, p.errorf(t, "expected a value or selector")
+}
+
+func (p *parser) parseList() (operand, error) {
+	items := make([]operand, 0)
+	if t := p.peek(); t.kind == tokenOperator && t.value == "]" {
+		p.next()
+		return &list{items: items}, nil
+	}
+	for {
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		t := p.next()
+		if t.kind == tokenOperator && t.value == "]" {
+			return &list{items: items}, nil
+		}
+		if t.kind != tokenOperator || t.value != "," {
+			return nil, p.errorf(t, "expected \",\" or \"]\"")
+		}
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"gopkg.in/yaml.v2"
+
+	"github.com/pluralsh/plural-cli/pkg/utils"
 )
 
 const (
@@ -38,7 +40,21 @@ type Config struct {
 	Endpoint        string `yaml:"endpoint"`
 	LockProfile     string `yaml:"lockProfile"`
 	ReportErrors    bool   `yaml:"reportErrors"`
-	metadata        *Metadata
+	// CredentialStore moves Token and ConsoleToken out of this file when set
+	CredentialStore CredentialStoreType `yaml:"credentialStore,omitempty" json:"credentialStore,omitempty"`
+	CredentialKey   string              `yaml:"credentialKey,omitempty" json:"credentialKey,omitempty"`
+	// SCMHosts maps self-hosted source control hosts onto the provider serving them
+	SCMHosts []SCMHost `yaml:"scmHosts,omitempty" json:"scmHosts,omitempty"`
+	metadata *Metadata
+}
+
+type SCMHost struct {
+	Host     string `yaml:"host" json:"host"`
+	Provider string `yaml:"provider" json:"provider"`
+	// APIURL overrides the API endpoint used to look up pull requests by commit
+	APIURL string `yaml:"apiUrl,omitempty" json:"apiUrl,omitempty"`
+	// TokenEnv names the environment variable holding the API token for the host
+	TokenEnv string `yaml:"tokenEnv,omitempty" json:"tokenEnv,omitempty"`
 }
 
 type VersionedConfig struct {
@@ -110,16 +126,25 @@ func Profiles() ([]*VersionedConfig, error) {
 }
 
 func Import(file string) (conf Config) {
+	conf, err := importConfig(file)
+	if err != nil {
+		utils.Warn("could not read credentials for %s: %s\n", file, err)
+	}
+	return
+}
+
+func importConfig(file string) (conf Config, err error) {
 	contents, err := os.ReadFile(file)
 	if err != nil {
-		return
+		return Config{}, nil
 	}
 
 	versioned := &VersionedConfig{Spec: &conf}
 	if err = yaml.Unmarshal(contents, versioned); err != nil {
-		return Config{}
+		return Config{}, nil
 	}
 	conf.metadata = versioned.Metadata
+	err = conf.loadCredentials()
 	return
 }
 
@@ -189,7 +214,12 @@ func PluralDir(p ...string) (string, error) {
 }
 
 func (c *Config) Save(filename string) error {
-	io, err := c.Marshal()
+	persisted, err := c.persisted(strings.TrimSuffix(filename, filepath.Ext(filename)))
+	if err != nil {
+		return err
+	}
+
+	io, err := persisted.Marshal()
 	if err != nil {
 		return err
 	}
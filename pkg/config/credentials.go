@@ -0,0 +1,436 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"gopkg.in/yaml.v2"
+
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+type CredentialStoreType string
+
+const (
+	PlaintextStore      CredentialStoreType = ""
+	FileStore           CredentialStoreType = "file"
+	PassphraseFileStore CredentialStoreType = "file-passphrase"
+	SecretServiceStore  CredentialStoreType = "secret-service"
+
+	credentialsFile        = "credentials.age"
+	passphraseFile         = "credentials.passphrase.age"
+	secretServiceName      = "plural-cli"
+	passphraseEnvVar       = "PLURAL_CREDENTIALS_PASSPHRASE"
+	tokenCredential        = "token"
+	consoleTokenCredential = "consoleToken"
+)
+
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore persists tokens outside of the plaintext config files
+type CredentialStore interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+var (
+	stores   = map[CredentialStoreType]CredentialStore{}
+	storesMu sync.Mutex
+)
+
+// ParseCredentialStore maps a user supplied store name onto its type, plaintext disables the store
+func ParseCredentialStore(name string) (CredentialStoreType, error) {
+	switch typ := CredentialStoreType(name); typ {
+	case FileStore, PassphraseFileStore, SecretServiceStore:
+		return typ, nil
+	case "plaintext":
+		return PlaintextStore, nil
+	}
+
+	return PlaintextStore, fmt.Errorf("unsupported credential store %q, must be one of plaintext, file, file-passphrase or secret-service", name)
+}
+
+// GetCredentialStore returns the store for the given type, stores are cached for the life of the process
+// so encrypted files are only decrypted, and passphrases only prompted for, once
+func GetCredentialStore(typ CredentialStoreType) (CredentialStore, error) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if store, ok := stores[typ]; ok {
+		return store, nil
+	}
+
+	var store CredentialStore
+	switch typ {
+	case FileStore:
+		store = &ageFileStore{file: credentialsFile, useIdentity: true}
+	case PassphraseFileStore:
+		store = &ageFileStore{file: passphraseFile}
+	case SecretServiceStore:
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, fmt.Errorf("the secret-service credential store requires secret-tool, install libsecret-tools")
+		}
+		store = &secretServiceStore{cache: map[string]string{}}
+	default:
+		return nil, fmt.Errorf("unsupported credential store %q", typ)
+	}
+
+	stores[typ] = store
+	return store, nil
+}
+
+// ageFileStore keeps all credentials in a single age encrypted yaml file in the plural dir, encrypted either to
+// the user's age identity or a passphrase
+type ageFileStore struct {
+	file        string
+	useIdentity bool
+	passphrase  string
+	creds       map[string]string
+}
+
+func (s *ageFileStore) Get(key string) (string, error) {
+	if err := s.load(); err != nil {
+		return "", err
+	}
+
+	val, ok := s.creds[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return val, nil
+}
+
+func (s *ageFileStore) Set(key, value string) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	s.creds[key] = value
+	return s.flush()
+}
+
+func (s *ageFileStore) Delete(key string) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	if _, ok := s.creds[key]; !ok {
+		return nil
+	}
+	delete(s.creds, key)
+	return s.flush()
+}
+
+func (s *ageFileStore) load() error {
+	if s.creds != nil {
+		return nil
+	}
+
+	path, err := PluralDir(s.file)
+	if err != nil {
+		return err
+	}
+
+	s.creds = map[string]string{}
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	identity, err := s.identity()
+	if err != nil {
+		return err
+	}
+
+	reader, err := age.Decrypt(bytes.NewReader(contents), identity)
+	if err != nil {
+		s.creds = nil
+		return fmt.Errorf("could not decrypt %s: %w", path, err)
+	}
+
+	decrypted, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(decrypted, &s.creds)
+}
+
+func (s *ageFileStore) flush() error {
+	recipient, err := s.recipient()
+	if err != nil {
+		return err
+	}
+
+	contents, err := yaml.Marshal(s.creds)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writer, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(contents); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	path, err := PluralDir(s.file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+func (s *ageFileStore) identity() (age.Identity, error) {
+	if s.useIdentity {
+		return s.userIdentity()
+	}
+
+	pass, err := s.readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	return age.NewScryptIdentity(pass)
+}
+
+func (s *ageFileStore) recipient() (age.Recipient, error) {
+	if s.useIdentity {
+		ident, err := s.userIdentity()
+		if err != nil {
+			return nil, err
+		}
+		return ident.Recipient(), nil
+	}
+
+	pass, err := s.readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	return age.NewScryptRecipient(pass)
+}
+
+// userIdentity reads the age identity created by `plural crypto setup-keys`
+func (s *ageFileStore) userIdentity() (*age.X25519Identity, error) {
+	path, err := PluralDir("identity")
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no age identity found at %s, run `plural crypto setup-keys` or use the file-passphrase credential store", path)
+	}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+		return age.ParseX25519Identity(line)
+	}
+	return nil, fmt.Errorf("no identity found in %s", path)
+}
+
+func (s *ageFileStore) readPassphrase() (string, error) {
+	if s.passphrase != "" {
+		return s.passphrase, nil
+	}
+
+	pass, ok := utils.GetEnvStringValue(passphraseEnvVar)
+	if !ok {
+		var err error
+		pass, err = utils.ReadPwd("Enter the passphrase for your plural credentials: ")
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if pass == "" {
+		return "", fmt.Errorf("the credentials passphrase cannot be empty, set it with %s", passphraseEnvVar)
+	}
+	s.passphrase = pass
+	return pass, nil
+}
+
+// secretServiceStore keeps credentials in the linux secret service (gnome keyring, kwallet) via secret-tool
+type secretServiceStore struct {
+	cache map[string]string
+}
+
+func (s *secretServiceStore) Get(key string) (string, error) {
+	if val, ok := s.cache[key]; ok {
+		return val, nil
+	}
+
+	cmd := exec.Command("secret-tool", "lookup", "service", secretServiceName, "key", key)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+			return "", ErrCredentialNotFound
+		}
+		return "", fmt.Errorf("secret-tool lookup failed: %w", err)
+	}
+
+	s.cache[key] = string(out)
+	return s.cache[key], nil
+}
+
+func (s *secretServiceStore) Set(key, value string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", "Plural CLI "+key, "service", secretServiceName, "key", key)
+	cmd.Stdin = strings.NewReader(value)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool store failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	s.cache[key] = value
+	return nil
+}
+
+func (s *secretServiceStore) Delete(key string) error {
+	delete(s.cache, key)
+	cmd := exec.Command("secret-tool", "clear", "service", secretServiceName, "key", key)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool clear failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// LoadCredential reads a credential, treating a missing entry as empty
+func LoadCredential(typ CredentialStoreType, key string) (string, error) {
+	store, err := GetCredentialStore(typ)
+	if err != nil {
+		return "", err
+	}
+
+	val, err := store.Get(key)
+	if errors.Is(err, ErrCredentialNotFound) {
+		return "", nil
+	}
+	return val, err
+}
+
+// StoreCredential writes a credential, deleting it instead if the value is empty
+func StoreCredential(typ CredentialStoreType, key, value string) error {
+	store, err := GetCredentialStore(typ)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		return store.Delete(key)
+	}
+	return store.Set(key, value)
+}
+
+func (c *Config) loadCredentials() (err error) {
+	if c.CredentialStore == PlaintextStore || c.CredentialKey == "" {
+		return nil
+	}
+
+	if c.Token, err = LoadCredential(c.CredentialStore, c.CredentialKey+"/"+tokenCredential); err != nil {
+		return err
+	}
+	c.ConsoleToken, err = LoadCredential(c.CredentialStore, c.CredentialKey+"/"+consoleTokenCredential)
+	return err
+}
+
+// persisted moves the tokens into the credential store under the given key and returns the config as it
+// should be written to disk
+func (c *Config) persisted(key string) (*Config, error) {
+	if c.CredentialStore == PlaintextStore {
+		return c, nil
+	}
+
+	if err := StoreCredential(c.CredentialStore, key+"/"+tokenCredential, c.Token); err != nil {
+		return nil, err
+	}
+	if err := StoreCredential(c.CredentialStore, key+"/"+consoleTokenCredential, c.ConsoleToken); err != nil {
+		return nil, err
+	}
+
+	c.CredentialKey = key
+	persisted := *c
+	persisted.Token = ""
+	persisted.ConsoleToken = ""
+	return &persisted, nil
+}
+
+func (c *Config) deleteCredentials() error {
+	if c.CredentialStore == PlaintextStore || c.CredentialKey == "" {
+		return nil
+	}
+
+	for _, name := range []string{tokenCredential, consoleTokenCredential} {
+		if err := StoreCredential(c.CredentialStore, c.CredentialKey+"/"+name, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateCredentials moves the tokens of the current config and every saved profile into the given store,
+// removing them from the previous one
+func MigrateCredentials(to CredentialStoreType) error {
+	files := []string{ConfigName}
+	profiles, err := Profiles()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, profile := range profiles {
+		if profile.Metadata != nil && profile.Metadata.Name != "" {
+			files = append(files, profile.Metadata.Name+".yml")
+		}
+	}
+
+	for _, file := range files {
+		path, err := PluralDir(file)
+		if err != nil {
+			return err
+		}
+		if !utils.Exists(path) {
+			continue
+		}
+
+		conf, err := importConfig(path)
+		if err != nil {
+			return err
+		}
+		if conf.CredentialStore == to {
+			continue
+		}
+
+		prior := conf
+		conf.CredentialStore = to
+		conf.CredentialKey = ""
+		if err := conf.Save(file); err != nil {
+			return err
+		}
+		if err := prior.deleteCredentials(); err != nil {
+			return err
+		}
+	}
+
+	config = nil
+	return nil
+}
@@ -0,0 +1,49 @@
+package config_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
+	pluraltest "github.com/pluralsh/plural-cli/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateCredentials(t *testing.T) {
+	dir, err := os.MkdirTemp("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	os.Setenv("HOME", dir)
+	defer os.Unsetenv("HOME")
+	os.Setenv("PLURAL_CREDENTIALS_PASSPHRASE", "passphrase")
+	defer os.Unsetenv("PLURAL_CREDENTIALS_PASSPHRASE")
+
+	defaultConfig := pluraltest.GenDefaultConfig()
+	err = defaultConfig.Save(config.ConfigName)
+	assert.NoError(t, err)
+	err = defaultConfig.SaveProfile("prod")
+	assert.NoError(t, err)
+
+	err = config.MigrateCredentials(config.PassphraseFileStore)
+	assert.NoError(t, err)
+
+	for _, file := range []string{config.ConfigName, "prod.yml"} {
+		contents, err := os.ReadFile(path.Join(dir, ".plural", file))
+		assert.NoError(t, err)
+		assert.Contains(t, string(contents), `token: ""`)
+
+		conf := config.Import(path.Join(dir, ".plural", file))
+		assert.Equal(t, defaultConfig.Token, conf.Token)
+		assert.Equal(t, config.PassphraseFileStore, conf.CredentialStore)
+	}
+	assert.FileExists(t, path.Join(dir, ".plural", "credentials.passphrase.age"))
+
+	err = config.MigrateCredentials(config.PlaintextStore)
+	assert.NoError(t, err)
+	result := config.Read()
+	assert.Equal(t, defaultConfig.Token, result.Token)
+	assert.Equal(t, config.PlaintextStore, result.CredentialStore)
+	assert.Empty(t, result.CredentialKey)
+}
@@ -1,6 +1,9 @@
 package console
 
 import (
+	"fmt"
+	"strings"
+
 	console "github.com/pluralsh/console/go/client"
 	"github.com/samber/lo"
 
@@ -26,3 +29,74 @@ func (c *consoleClient) ListAgentRuns(first int64) ([]*console.AgentRunMinimalFr
 		return item.GetNode()
 	}), nil
 }
+
+func (c *consoleClient) ListAgentRunsPage(after *string, first int64) (*console.ListAgentRunsMinimal_AgentRuns, error) {
+	result, err := c.client.ListAgentRunsMinimal(c.ctx, after, &first, nil, nil)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "ListAgentRuns")
+	}
+
+	return result.GetAgentRuns(), nil
+}
+
+func (c *consoleClient) GetAgentRunMessages(id string) ([]*console.AgentMessageFragment, error) {
+	result, err := c.client.GetAgentRun(c.ctx, id)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "GetAgentRun")
+	}
+	run := result.GetAgentRun()
+	if run == nil {
+		return nil, fmt.Errorf("agent run %s not found", id)
+	}
+
+	return run.GetMessages(), nil
+}
+
+func (c *consoleClient) CancelAgentRun(id string) error {
+	if _, err := c.client.CancelAgentRun(c.ctx, id); err != nil {
+		return api.GetErrorResponse(err, "CancelAgentRun")
+	}
+
+	return nil
+}
+
+func (c *consoleClient) GetAgentRuntime(runtime console.AgentRuntimeType) (*console.AgentRuntimeFragment, error) {
+	result, err := c.client.ListAgentRuntimes(c.ctx, nil, lo.ToPtr(int64(100)), nil, nil, nil, &runtime)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "ListAgentRuntimes")
+	}
+
+	for _, edge := range result.GetAgentRuntimes().GetEdges() {
+		if node := edge.GetNode(); node != nil && node.Type == runtime {
+			return node, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s agent runtime is configured in this console", strings.ToLower(string(runtime)))
+}
+
+func (c *consoleClient) CreateAgentRun(runtimeID string, attributes console.AgentRunAttributes) (string, error) {
+	result, err := c.client.CreateAgentRun(c.ctx, runtimeID, attributes)
+	if err != nil {
+		return "", api.GetErrorResponse(err, "CreateAgentRun")
+	}
+	run := result.GetCreateAgentRun()
+	if run == nil {
+		return "", fmt.Errorf("returned object [CreateAgentRun] is nil")
+	}
+
+	return run.ID, nil
+}
+
+func (c *consoleClient) AgentRunSessionUploadURL(id string) (string, error) {
+	result, err := c.client.CreateAgentRunUpload(c.ctx, id)
+	if err != nil {
+		return "", api.GetErrorResponse(err, "CreateAgentRunUpload")
+	}
+	upload := result.GetCreateAgentRunUpload()
+	if upload == nil || upload.GetSession() == nil {
+		return "", fmt.Errorf("returned object [CreateAgentRunUpload] is nil")
+	}
+
+	return *upload.GetSession(), nil
+}
@@ -7,11 +7,15 @@ import (
 	"path/filepath"
 
 	"sigs.k8s.io/yaml"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
+	"github.com/pluralsh/plural-cli/pkg/utils"
 )
 
 const (
-	pluralDir  = ".plural"
-	ConfigName = "console.yml"
+	pluralDir     = ".plural"
+	ConfigName    = "console.yml"
+	credentialKey = "console/token"
 )
 
 type VersionedConfig struct {
@@ -23,6 +27,8 @@ type VersionedConfig struct {
 type Config struct {
 	Url   string `json:"url"`
 	Token string `json:"token"`
+	// CredentialStore moves Token out of this file when set, defaulting to the store of the plural config
+	CredentialStore config.CredentialStoreType `json:"credentialStore,omitempty"`
 }
 
 func configFile() string {
@@ -40,6 +46,12 @@ func ReadConfig() (conf Config) {
 	if err = yaml.Unmarshal(contents, versioned); err != nil {
 		return Config{}
 	}
+
+	if conf.CredentialStore != config.PlaintextStore {
+		if conf.Token, err = config.LoadCredential(conf.CredentialStore, credentialKey); err != nil {
+			utils.Warn("could not read console credentials: %s\n", err)
+		}
+	}
 	return
 }
 
@@ -61,10 +73,22 @@ func (conf *Config) Save() error {
 		return err
 	}
 
+	if conf.CredentialStore == config.PlaintextStore {
+		conf.CredentialStore = config.Read().CredentialStore
+	}
+
+	persisted := *conf
+	if conf.CredentialStore != config.PlaintextStore {
+		if err := config.StoreCredential(conf.CredentialStore, credentialKey, conf.Token); err != nil {
+			return err
+		}
+		persisted.Token = ""
+	}
+
 	versioned := &VersionedConfig{
 		ApiVersion: "platform.plural.sh/v1alpha1",
 		Kind:       "Console",
-		Spec:       conf,
+		Spec:       &persisted,
 	}
 	io, err := yaml.Marshal(versioned)
 	if err != nil {
@@ -78,3 +102,27 @@ func (conf *Config) Save() error {
 
 	return os.WriteFile(f, io, 0644)
 }
+
+// MigrateCredentials moves the console token into the given store, removing it from the previous one
+func MigrateCredentials(to config.CredentialStoreType) error {
+	if _, err := os.Stat(configFile()); os.IsNotExist(err) {
+		return nil
+	}
+
+	conf := ReadConfig()
+	prior := conf.CredentialStore
+	if prior == to {
+		return nil
+	}
+
+	// the plural config is migrated first, so saving with a plaintext store will not fall back to the old one
+	conf.CredentialStore = to
+	if err := conf.Save(); err != nil {
+		return err
+	}
+
+	if prior != config.PlaintextStore {
+		return config.StoreCredential(prior, credentialKey, "")
+	}
+	return nil
+}
@@ -59,16 +59,36 @@ type ConsoleClient interface {
 	GetGlobalSettingsMinimal() (*consoleclient.DeploymentSettingsFragment, error)
 	GetAgentRun(id string) (*consoleclient.AgentRunMinimalFragment, error)
 	ListAgentRuns(first int64) ([]*consoleclient.AgentRunMinimalFragment, error)
+	ListAgentRunsPage(after *string, first int64) (*consoleclient.ListAgentRunsMinimal_AgentRuns, error)
+	GetAgentRunMessages(id string) ([]*consoleclient.AgentMessageFragment, error)
+	CancelAgentRun(id string) error
+	GetAgentRuntime(runtime consoleclient.AgentRuntimeType) (*consoleclient.AgentRuntimeFragment, error)
+	CreateAgentRun(runtimeID string, attributes consoleclient.AgentRunAttributes) (string, error)
+	AgentRunSessionUploadURL(id string) (string, error)
 	ListStackRuns(stackID string) (*consoleclient.ListStackRuns, error)
 	CreatePullRequest(id string, branch, context *string) (*consoleclient.PullRequestFragment, error)
+	ListPullRequests(clusterID, serviceID, after *string, first int64) ([]*consoleclient.PullRequestFragment, *consoleclient.PageInfoFragment, error)
+	GetPullRequest(id string) (*consoleclient.PullRequestFragment, error)
 	CreateWorkbenchPRFollowup(url, prompt string) (string, error)
 	EnqueueWorkbenchPRFollowup(url, prompt string, deferBy time.Duration) (*consoleclient.EnqueueWorkbenchPrFollowup_EnqueueWorkbenchPrFollowup, error)
+	ListWorkbenches(first int64) ([]*consoleclient.WorkbenchTinyFragment, error)
+	ListWorkbenchJobs(workbenchID string, after *string, first int64) ([]*consoleclient.WorkbenchJobTinyFragment, *consoleclient.PageInfoFragment, error)
+	GetWorkbenchJob(id string) (*consoleclient.WorkbenchJobTinyFragment, error)
+	ListWorkbenchJobActivities(id string, after *string, first int64) ([]*consoleclient.WorkbenchJobActivityFragment, *consoleclient.PageInfoFragment, error)
+	CreateWorkbenchMessage(jobID, prompt string) (string, error)
+	ListWorkbenchQueuedPrompts(jobID string) ([]*consoleclient.QueuedPromptFragment, error)
+	DeleteQueuedPrompt(id string) error
 	GetPrAutomationByName(name string) (*consoleclient.PrAutomationFragment, error)
 	CreateBootstrapToken(attributes consoleclient.BootstrapTokenAttributes) (string, error)
 	CreateClusterRegistration(attributes consoleclient.ClusterRegistrationCreateAttributes) (*consoleclient.ClusterRegistrationFragment, error)
 	IsClusterRegistrationComplete(machineID string) (bool, *consoleclient.ClusterRegistrationFragment)
 	GetUser(email string) (*consoleclient.UserFragment, error)
 	ListStacks() (*consoleclient.ListInfrastructureStacks, error)
+	GetStack(id string) (*consoleclient.InfrastructureStackFragment, error)
+	GetStackRun(id string) (*consoleclient.StackRunFragment, error)
+	ApproveStackRun(id string) error
+	RestartStackRun(id string) (string, error)
+	TriggerStackRun(stackID string) (string, error)
 }
 
 type authedTransport struct {
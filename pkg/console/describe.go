@@ -150,6 +150,40 @@ func DescribeService(service *consoleclient.ServiceDeploymentExtended) (string,
 	})
 }
 
+func DescribeStack(stack *consoleclient.InfrastructureStackFragment) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		w := NewPrefixWriter(out)
+		if stack.ID != nil {
+			w.Write(Level0, "Id:\t%s\n", *stack.ID)
+		}
+		w.Write(Level0, "Name:\t%s\n", stack.Name)
+		w.Write(Level0, "Type:\t%s\n", stack.Type)
+		if stack.DeletedAt != nil {
+			w.Write(Level0, "Status:\t%s, terminating since %s\n", stack.Status, *stack.DeletedAt)
+		} else {
+			w.Write(Level0, "Status:\t%s\n", stack.Status)
+		}
+		approval := false
+		if stack.Approval != nil {
+			approval = *stack.Approval
+		}
+		w.Write(Level0, "Approval:\t%v\n", approval)
+		if stack.Paused != nil {
+			w.Write(Level0, "Paused:\t%v\n", *stack.Paused)
+		}
+		if stack.Cluster != nil {
+			w.Write(Level0, "Cluster:\t%s\n", stack.Cluster.Name)
+		}
+		if stack.Repository != nil {
+			w.Write(Level0, "Repository:\t%s\n", stack.Repository.URL)
+		}
+		w.Write(Level0, "Git:\t\n")
+		w.Write(Level1, "Ref:\t%s\n", stack.Git.Ref)
+		w.Write(Level1, "Folder:\t%s\n", stack.Git.Folder)
+		return nil
+	})
+}
+
 var maxConfigLen = 140
 
 func printConfigMultiline(w PrefixWriter, title string, configurations map[string]string) {
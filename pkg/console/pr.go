@@ -16,6 +16,34 @@ func (c *consoleClient) CreatePullRequest(id string, branch, context *string) (*
 	return result.CreatePullRequest, nil
 }
 
+func (c *consoleClient) ListPullRequests(clusterID, serviceID, after *string, first int64) ([]*consoleclient.PullRequestFragment, *consoleclient.PageInfoFragment, error) {
+	result, err := c.client.ListPullRequests(c.ctx, after, &first, nil, nil, clusterID, serviceID)
+	if err != nil {
+		return nil, nil, api.GetErrorResponse(err, "ListPullRequests")
+	}
+
+	pullRequests := make([]*consoleclient.PullRequestFragment, 0)
+	for _, edge := range result.GetPullRequests().GetEdges() {
+		if node := edge.GetNode(); node != nil {
+			pullRequests = append(pullRequests, node)
+		}
+	}
+	pageInfo := result.GetPullRequests().PageInfo
+	return pullRequests, &pageInfo, nil
+}
+
+func (c *consoleClient) GetPullRequest(id string) (*consoleclient.PullRequestFragment, error) {
+	result, err := c.client.GetPullRequest(c.ctx, id)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "GetPullRequest")
+	}
+	if result.PullRequest == nil {
+		return nil, fmt.Errorf("pull request %s not found", id)
+	}
+
+	return result.PullRequest, nil
+}
+
 func (c *consoleClient) GetPrAutomationByName(name string) (*consoleclient.PrAutomationFragment, error) {
 	result, err := c.client.GetPrAutomationByName(c.ctx, name)
 	if err != nil {
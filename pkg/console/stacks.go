@@ -1,8 +1,12 @@
 package console
 
 import (
+	"fmt"
+
 	gqlclient "github.com/pluralsh/console/go/client"
 	"github.com/samber/lo"
+
+	"github.com/pluralsh/plural-cli/pkg/api"
 )
 
 func (c *consoleClient) ListStackRuns(stackID string) (*gqlclient.ListStackRuns, error) {
@@ -12,3 +16,59 @@ func (c *consoleClient) ListStackRuns(stackID string) (*gqlclient.ListStackRuns,
 func (c *consoleClient) ListStacks() (*gqlclient.ListInfrastructureStacks, error) {
 	return c.client.ListInfrastructureStacks(c.ctx, nil, lo.ToPtr(int64(100)), nil, nil)
 }
+
+func (c *consoleClient) GetStack(id string) (*gqlclient.InfrastructureStackFragment, error) {
+	result, err := c.client.GetInfrastructureStack(c.ctx, &id, nil)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "GetInfrastructureStack")
+	}
+	if result.InfrastructureStack == nil {
+		return nil, fmt.Errorf("stack %s not found", id)
+	}
+
+	return result.InfrastructureStack, nil
+}
+
+func (c *consoleClient) GetStackRun(id string) (*gqlclient.StackRunFragment, error) {
+	result, err := c.client.GetStackRun(c.ctx, id)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "GetStackRun")
+	}
+	if result.StackRun == nil {
+		return nil, fmt.Errorf("stack run %s not found", id)
+	}
+
+	return result.StackRun, nil
+}
+
+func (c *consoleClient) ApproveStackRun(id string) error {
+	if _, err := c.client.ApproveStackRun(c.ctx, id); err != nil {
+		return api.GetErrorResponse(err, "ApproveStackRun")
+	}
+
+	return nil
+}
+
+func (c *consoleClient) RestartStackRun(id string) (string, error) {
+	result, err := c.client.RestartStackRun(c.ctx, id)
+	if err != nil {
+		return "", api.GetErrorResponse(err, "RestartStackRun")
+	}
+	if result.RestartStackRun == nil {
+		return "", fmt.Errorf("returned object [RestartStackRun] is nil")
+	}
+
+	return result.RestartStackRun.ID, nil
+}
+
+func (c *consoleClient) TriggerStackRun(stackID string) (string, error) {
+	result, err := c.client.TriggerRun(c.ctx, stackID)
+	if err != nil {
+		return "", api.GetErrorResponse(err, "TriggerRun")
+	}
+	if result.TriggerRun == nil {
+		return "", fmt.Errorf("returned object [TriggerRun] is nil")
+	}
+
+	return result.TriggerRun.ID, nil
+}
@@ -38,3 +38,105 @@ func (c *consoleClient) EnqueueWorkbenchPRFollowup(url, prompt string, deferBy t
 
 	return fragment, nil
 }
+
+func (c *consoleClient) ListWorkbenches(first int64) ([]*consoleclient.WorkbenchTinyFragment, error) {
+	result, err := c.client.ListWorkbenches(c.ctx, nil, &first, nil, nil, nil)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "ListWorkbenches")
+	}
+
+	workbenches := make([]*consoleclient.WorkbenchTinyFragment, 0)
+	for _, edge := range result.GetWorkbenches().GetEdges() {
+		if node := edge.GetNode(); node != nil {
+			workbenches = append(workbenches, node)
+		}
+	}
+	return workbenches, nil
+}
+
+func (c *consoleClient) ListWorkbenchJobs(workbenchID string, after *string, first int64) ([]*consoleclient.WorkbenchJobTinyFragment, *consoleclient.PageInfoFragment, error) {
+	result, err := c.client.ListWorkbenchJobs(c.ctx, workbenchID, after, &first, nil, nil)
+	if err != nil {
+		return nil, nil, api.GetErrorResponse(err, "ListWorkbenchJobs")
+	}
+	workbench := result.GetWorkbench()
+	if workbench == nil {
+		return nil, nil, fmt.Errorf("workbench %s not found", workbenchID)
+	}
+
+	jobs := make([]*consoleclient.WorkbenchJobTinyFragment, 0)
+	for _, edge := range workbench.GetRuns().GetEdges() {
+		if node := edge.GetNode(); node != nil {
+			jobs = append(jobs, node)
+		}
+	}
+	pageInfo := workbench.GetRuns().PageInfo
+	return jobs, &pageInfo, nil
+}
+
+func (c *consoleClient) GetWorkbenchJob(id string) (*consoleclient.WorkbenchJobTinyFragment, error) {
+	result, err := c.client.GetWorkbenchJobTiny(c.ctx, id)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "GetWorkbenchJob")
+	}
+	job := result.GetWorkbenchJob()
+	if job == nil {
+		return nil, fmt.Errorf("workbench job %s not found", id)
+	}
+
+	return job, nil
+}
+
+func (c *consoleClient) ListWorkbenchJobActivities(id string, after *string, first int64) ([]*consoleclient.WorkbenchJobActivityFragment, *consoleclient.PageInfoFragment, error) {
+	result, err := c.client.GetWorkbenchJobActivities(c.ctx, id, after, &first, nil, nil)
+	if err != nil {
+		return nil, nil, api.GetErrorResponse(err, "GetWorkbenchJobActivities")
+	}
+	job := result.GetWorkbenchJob()
+	if job == nil {
+		return nil, nil, fmt.Errorf("workbench job %s not found", id)
+	}
+
+	activities := make([]*consoleclient.WorkbenchJobActivityFragment, 0)
+	for _, edge := range job.GetActivities().GetEdges() {
+		if node := edge.GetNode(); node != nil {
+			activities = append(activities, node)
+		}
+	}
+	pageInfo := job.GetActivities().PageInfo
+	return activities, &pageInfo, nil
+}
+
+func (c *consoleClient) CreateWorkbenchMessage(jobID, prompt string) (string, error) {
+	result, err := c.client.CreateWorkbenchMessage(c.ctx, jobID, consoleclient.WorkbenchMessageAttributes{Prompt: prompt})
+	if err != nil {
+		return "", api.GetErrorResponse(err, "CreateWorkbenchMessage")
+	}
+	activity := result.GetCreateWorkbenchMessage()
+	if activity == nil {
+		return "", fmt.Errorf("returned object [CreateWorkbenchMessage] is nil")
+	}
+
+	return activity.GetID(), nil
+}
+
+func (c *consoleClient) ListWorkbenchQueuedPrompts(jobID string) ([]*consoleclient.QueuedPromptFragment, error) {
+	result, err := c.client.GetWorkbenchJobQueuedPrompts(c.ctx, jobID)
+	if err != nil {
+		return nil, api.GetErrorResponse(err, "GetWorkbenchJobQueuedPrompts")
+	}
+	job := result.GetWorkbenchJob()
+	if job == nil {
+		return nil, fmt.Errorf("workbench job %s not found", jobID)
+	}
+
+	return job.GetQueuedPrompts(), nil
+}
+
+func (c *consoleClient) DeleteQueuedPrompt(id string) error {
+	if _, err := c.client.DeleteQueuedPrompt(c.ctx, id); err != nil {
+		return api.GetErrorResponse(err, "DeleteQueuedPrompt")
+	}
+
+	return nil
+}
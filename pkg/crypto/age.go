@@ -167,6 +167,92 @@ func SetupAge(client api.Client, emails []string) error {
 	return ageConfig.WriteKeyFile(keyPath, keydata)
 }
 
+// RevokeAge removes the identities for the given emails from the age config and re-wraps the repo key
+// for the remaining recipients only.  Note that revoked users may still hold a copy of the current aes key,
+// so this should be followed by RotateKey if they must not be able to decrypt future commits.
+func RevokeAge(emails []string) error {
+	ageConfig, err := setupAgeConfig()
+	if err != nil {
+		return err
+	}
+
+	ident, err := Identity()
+	if err != nil {
+		return err
+	}
+
+	revoked := containers.ToSet[string](emails)
+	self := ident.Recipient().String()
+	remaining := make([]*AgeIdentity, 0, len(ageConfig.Identities))
+	found := containers.NewSet[string]()
+	for _, id := range ageConfig.Identities {
+		if !revoked.Has(id.Email) {
+			remaining = append(remaining, id)
+			continue
+		}
+
+		if id.Key == self {
+			return fmt.Errorf("cannot revoke %s, it is the identity currently used to decrypt this repo", id.Email)
+		}
+		found.Add(id.Email)
+	}
+
+	if missing := revoked.Difference(found); missing.Len() > 0 {
+		return fmt.Errorf("the users %v are not recipients of this repo", missing.List())
+	}
+
+	prov, err := BuildAgeProvider()
+	if err != nil {
+		return err
+	}
+
+	ageConfig.Identities = remaining
+	keydata, err := prov.Key.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return ageConfig.WriteKeyFile(pathing.SanitizeFilepath(filepath.Join(cryptPath(), "key")), keydata)
+}
+
+// RotateKey generates a fresh aes key and wraps it for the current age recipients.  Files already committed
+// must be re-encrypted by the caller afterwards.
+func RotateKey() (*AgeProvider, error) {
+	ageConfig, err := setupAgeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	ident, err := Identity()
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := RandStr(32)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &AESKey{Key: str}
+	keydata, err := key.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ageConfig.WriteKeyFile(pathing.SanitizeFilepath(filepath.Join(cryptPath(), "key")), keydata); err != nil {
+		return nil, err
+	}
+
+	if utils.Exists(getKeyValidatorPath()) {
+		kv := KeyValidator{KeyID: key.ID()}
+		if err := kv.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &AgeProvider{Identity: ident, Key: key}, nil
+}
+
 func (a *Age) Recipients() []age.Recipient {
 	recipients := make([]age.Recipient, 0)
 
@@ -118,3 +118,71 @@ identities: []
 		})
 	}
 }
+
+func TestRevokeAge(t *testing.T) {
+	tests := []struct {
+		name          string
+		expectedError string
+		expected      []string
+		revoke        []string
+	}{
+		{
+			name:     `remove user from identities.yaml`,
+			revoke:   []string{"test@plural.sh"},
+			expected: []string{"", "test-1@plural.sh"},
+		},
+		{
+			name:          `when the user is not a recipient`,
+			revoke:        []string{"missing@plural.sh"},
+			expectedError: "the users [missing@plural.sh] are not recipients of this repo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "config")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			os.Setenv("HOME", dir)
+			defer os.Unsetenv("HOME")
+
+			err = os.Chdir(dir)
+			assert.NoError(t, err)
+			_, err = git.Init()
+			assert.NoError(t, err)
+
+			err = os.MkdirAll(path.Join(dir, ".plural"), os.ModePerm)
+			assert.NoError(t, err)
+
+			client := mocks.NewClient(t)
+			client.On("ListKeys", mock.Anything).Return([]*api.PublicKey{
+				{Content: "age1wqc2hk954ukemelys5gxdwlqve8ev0e88hvl3cjhfcvq65gwgvsqkmq9dn", User: &api.User{Email: "test@plural.sh"}},
+				{Content: "age19lm6v2l4czn0rlhr7xy3g7ek8w4z7dn9qvestez8n5x6yxrs3v2semmafe", User: &api.User{Email: "test-1@plural.sh"}},
+			}, nil)
+
+			err = crypto.SetupAge(client, []string{"test@plural.sh", "test-1@plural.sh"})
+			assert.NoError(t, err)
+
+			err = crypto.RevokeAge(test.revoke)
+			if test.expectedError != "" {
+				assert.Equal(t, test.expectedError, err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			age := &crypto.Age{}
+			contents, err := os.ReadFile(pathing.SanitizeFilepath(filepath.Join(dir, ".plural-crypt", "identities.yml")))
+			assert.NoError(t, err)
+			err = yaml.Unmarshal(contents, age)
+			assert.NoError(t, err)
+			emails := []string{}
+			for _, id := range age.Identities {
+				emails = append(emails, id.Email)
+			}
+			assert.ElementsMatch(t, test.expected, emails)
+
+			_, err = crypto.BuildAgeProvider()
+			assert.NoError(t, err)
+		})
+	}
+}
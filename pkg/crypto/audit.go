@@ -0,0 +1,434 @@
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pluralsh/plural-cli/pkg/utils/git"
+)
+
+const (
+	cryptFilter = "plural-crypt"
+	// DefaultEntropyCutoff is the shannon entropy above which a string is reported as a likely secret
+	DefaultEntropyCutoff  = 4.5
+	minEntropyTokenLength = 24
+	binarySniffLength     = 8000
+)
+
+type secretRule struct {
+	name string
+	re   *regexp.Regexp
+	// group is the submatch holding the secret itself
+	group int
+}
+
+var secretRules = []secretRule{
+	{"private-key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY( BLOCK)?-----`), 0},
+	{"aws-access-key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), 0},
+	{"gitlab-token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}\b`), 0},
+	{"slack-token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9\-]{10,}\b`), 0},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`), 0},
+	{"age-secret-key", regexp.MustCompile(`AGE-SECRET-KEY-1[0-9A-Z]{58}`), 0},
+	{"generic-secret", regexp.MustCompile(`(?i)(password|passwd|secret|token|api[_-]?key|access[_-]?key)["']?\s*[:=]\s*["']?([^\s"'#{}$]{8,})`), 2},
+}
+
+var entropyToken = regexp.MustCompile(fmt.Sprintf(`[A-Za-z0-9+/=_\-]{%d,}`, minEntropyTokenLength))
+
+// auditSkip lists files that routinely contain high entropy data which isn't secret
+var auditSkip = []string{"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Chart.lock", "crypto.yml", ".keyid"}
+
+type AuditFinding struct {
+	Path   string
+	Line   int
+	Rule   string
+	Match  string
+	Commit string
+}
+
+type AuditReport struct {
+	// Findings are likely secrets in files that are not encrypted when committed
+	Findings []*AuditFinding
+	// Unencrypted are files covered by the plural-crypt filter that are committed in plaintext in HEAD
+	Unencrypted []string
+	// Suggestions are .gitattributes lines that would encrypt the files with findings
+	Suggestions []string
+}
+
+type AuditOptions struct {
+	History bool
+	Entropy float64
+}
+
+func (r *AuditReport) Issues() int {
+	return len(r.Findings) + len(r.Unencrypted)
+}
+
+// Audit scans the working tree, and optionally git history, of the current repo for secrets that would be
+// committed without passing through the plural-crypt filter
+func Audit(opts AuditOptions) (*AuditReport, error) {
+	if opts.Entropy <= 0 {
+		opts.Entropy = DefaultEntropyCutoff
+	}
+
+	root, err := git.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := gitOutput(root, nil, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	files := splitNull(out)
+
+	covered, err := coveredFiles(root, files)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AuditReport{}
+	suggested := map[string]bool{}
+	for _, file := range files {
+		if covered[file] || skipAudit(file) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil {
+			continue
+		}
+
+		findings := scanContent(file, content, opts.Entropy)
+		if len(findings) > 0 && !suggested[file] {
+			suggested[file] = true
+			report.Suggestions = append(report.Suggestions, fmt.Sprintf("%s filter=%s diff=%s", attributesPattern(file), cryptFilter, cryptFilter))
+		}
+		report.Findings = append(report.Findings, findings...)
+	}
+
+	report.Unencrypted, err = unencryptedInHead(root, covered)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.History {
+		findings, err := scanHistory(root, opts.Entropy)
+		if err != nil {
+			return nil, err
+		}
+		report.Findings = append(report.Findings, findings...)
+	}
+
+	return report, nil
+}
+
+func coveredFiles(root string, files []string) (map[string]bool, error) {
+	covered := map[string]bool{}
+	if len(files) == 0 {
+		return covered, nil
+	}
+
+	input := strings.Join(files, "\x00") + "\x00"
+	out, err := gitOutput(root, strings.NewReader(input), "check-attr", "-z", "--stdin", "filter")
+	if err != nil {
+		return nil, err
+	}
+
+	// -z output is a sequence of <path> NUL <attribute> NUL <value> NUL triples
+	fields := splitNull(out)
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == cryptFilter {
+			covered[fields[i]] = true
+		}
+	}
+	return covered, nil
+}
+
+func unencryptedInHead(root string, covered map[string]bool) ([]string, error) {
+	out, err := gitOutput(root, nil, "ls-tree", "-r", "-z", "--name-only", "HEAD")
+	if err != nil {
+		// a repo without commits has nothing to verify
+		return nil, nil
+	}
+
+	paths := make([]string, 0)
+	for _, file := range splitNull(out) {
+		if covered[file] {
+			paths = append(paths, file)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	var input strings.Builder
+	for _, path := range paths {
+		input.WriteString("HEAD:" + path + "\n")
+	}
+
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = root
+	cmd.Stdin = strings.NewReader(input.String())
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	waited := false
+	defer reap(cmd, &waited)
+
+	reader := bufio.NewReader(stdout)
+	unencrypted := make([]string, 0)
+	for _, path := range paths {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		// each object is emitted as "<sha> <type> <size>\n<content>\n"
+		parts := strings.Fields(line)
+		if len(parts) != 3 {
+			continue
+		}
+		size, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, err
+		}
+		if size > 0 && !IsEncrypted(content) {
+			unencrypted = append(unencrypted, path)
+		}
+	}
+
+	waited = true
+	return unencrypted, cmd.Wait()
+}
+
+func scanHistory(root string, cutoff float64) ([]*AuditFinding, error) {
+	// textconv would decrypt covered files, and encrypted blobs are binary so never show up in the patch
+	cmd := exec.Command("git", "log", "-p", "--all", "--no-color", "--no-textconv", "--no-ext-diff", "-U0", "--format=commit %H")
+	cmd.Dir = root
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	waited := false
+	defer reap(cmd, &waited)
+
+	findings := make([]*AuditFinding, 0)
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	var commit, path string
+	var line int
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "commit "):
+			commit = strings.TrimPrefix(text, "commit ")
+		case strings.HasPrefix(text, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+		case strings.HasPrefix(text, "@@ "):
+			line = hunkStart(text)
+		case strings.HasPrefix(text, "+"):
+			if path != "" && !skipAudit(path) {
+				if finding := scanLine(path, line, text[1:], cutoff); finding != nil {
+					key := fmt.Sprintf("%s:%s:%s", path, finding.Rule, finding.Match)
+					if !seen[key] {
+						seen[key] = true
+						finding.Commit = commit
+						findings = append(findings, finding)
+					}
+				}
+			}
+			line++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	waited = true
+	return findings, cmd.Wait()
+}
+
+// reap kills and waits for a started git process when returning early, so it doesn't linger as a zombie
+func reap(cmd *exec.Cmd, waited *bool) {
+	if *waited {
+		return
+	}
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+}
+
+// hunkStart parses the first line of the new file range from a hunk header like @@ -1,2 +3,4 @@
+func hunkStart(header string) int {
+	for _, field := range strings.Fields(header) {
+		if strings.HasPrefix(field, "+") {
+			start, _, _ := strings.Cut(field[1:], ",")
+			if n, err := strconv.Atoi(start); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+func scanContent(path string, content []byte, cutoff float64) []*AuditFinding {
+	head := content
+	if len(head) > binarySniffLength {
+		head = head[:binarySniffLength]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	findings := make([]*AuditFinding, 0)
+	for i, line := range strings.Split(string(content), "\n") {
+		if finding := scanLine(path, i+1, line, cutoff); finding != nil {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func scanLine(path string, line int, text string, cutoff float64) *AuditFinding {
+	for _, rule := range secretRules {
+		if match := rule.re.FindStringSubmatch(text); match != nil {
+			return &AuditFinding{Path: path, Line: line, Rule: rule.name, Match: redact(match[rule.group])}
+		}
+	}
+
+	for _, token := range entropyToken.FindAllString(text, -1) {
+		if shannonEntropy(token) >= cutoff {
+			return &AuditFinding{Path: path, Line: line, Rule: "high-entropy-string", Match: redact(token)}
+		}
+	}
+	return nil
+}
+
+func shannonEntropy(str string) float64 {
+	counts := map[rune]int{}
+	for _, r := range str {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len(str))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func redact(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return secret[:4] + "****"
+}
+
+func skipAudit(path string) bool {
+	if strings.HasPrefix(path, ".plural-crypt/") || strings.HasPrefix(path, ".git/") {
+		return true
+	}
+
+	base := filepath.Base(path)
+	for _, skip := range auditSkip {
+		if base == skip {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendGitAttributes adds the given lines to the repo's .gitattributes, skipping any already present
+func AppendGitAttributes(lines []string) error {
+	root, err := git.Root()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(root, ".gitattributes")
+	existing := map[string]bool{}
+	prefix := ""
+	if contents, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(contents), "\n") {
+			existing[strings.TrimSpace(line)] = true
+		}
+		if len(contents) > 0 && !bytes.HasSuffix(contents, []byte("\n")) {
+			prefix = "\n"
+		}
+	}
+
+	additions := make([]string, 0)
+	for _, line := range lines {
+		if !existing[line] {
+			additions = append(additions, line)
+		}
+	}
+	if len(additions) == 0 {
+		return nil
+	}
+	sort.Strings(additions)
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(prefix + strings.Join(additions, "\n") + "\n")
+	return err
+}
+
+// attributesPattern turns path into a .gitattributes pattern matching only it, glob characters are escaped and
+// paths with whitespace or quotes are quoted C style, since whitespace separates the pattern from the attributes
+func attributesPattern(path string) string {
+	pattern := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(path)
+	if strings.HasPrefix(pattern, "#") || strings.HasPrefix(pattern, "!") {
+		pattern = `\` + pattern
+	}
+	if !strings.ContainsAny(pattern, " \t\"") {
+		return pattern
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\t", `\t`).Replace(pattern) + `"`
+}
+
+func gitOutput(root string, stdin io.Reader, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("command %s failed with output:\n\n%s", cmd.String(), stderr.String())
+	}
+	return string(out), nil
+}
+
+func splitNull(out string) []string {
+	return strings.FieldsFunc(out, func(r rune) bool { return r == 0 })
+}
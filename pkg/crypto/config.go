@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -14,10 +15,19 @@ type Config struct {
 	Type    IdentityType
 	Id      string
 	Context *Context
+	// Format is the ciphertext format the clean filter writes, set it to v2 once every collaborator's cli can
+	// read the streaming format.  Both formats are always decrypted.
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
 }
 
+const (
+	FormatLegacy = "v1"
+	FormatStream = "v2"
+)
+
 type Context struct {
 	Key *KeyConfig `yaml:"key" json:"key"`
+	KMS *KMSConfig `yaml:"kms,omitempty" json:"kms,omitempty"`
 }
 
 type KeyConfig struct {
@@ -40,7 +50,33 @@ func readConfig() (conf *Config, err error) {
 	return
 }
 
+// StreamFormat reports whether the repo opted into writing the v2 streaming format
+func StreamFormat() (bool, error) {
+	if !utils.Exists(configPath()) {
+		return false, nil
+	}
+
+	conf, err := readConfig()
+	if err != nil {
+		return false, err
+	}
+
+	switch conf.Format {
+	case "", FormatLegacy:
+		return false, nil
+	case FormatStream:
+		return true, nil
+	}
+	return false, fmt.Errorf("unsupported format %q in crypto.yml, must be one of %s or %s", conf.Format, FormatLegacy, FormatStream)
+}
+
 func Build() (prov Provider, err error) {
+	// kms backed repos never write a plaintext key to the repo or ~/.plural, the unwrapped key is only cached in
+	// memory and in the user's credential store
+	if conf, err := readConfig(); err == nil && conf.Type == KMS {
+		return buildVerifiedKMSProvider(conf)
+	}
+
 	key, err := Materialize()
 	if err != nil {
 		return
@@ -82,3 +118,21 @@ func Build() (prov Provider, err error) {
 func fallbackProvider(key *AESKey) (*KeyProvider, error) {
 	return &KeyProvider{key: key.Key}, nil
 }
+
+func buildVerifiedKMSProvider(conf *Config) (Provider, error) {
+	keyID, err := GetKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	prov, err := buildKMSProvider(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyID != "" && prov.ID() != keyID {
+		return nil, errFingerprint
+	}
+
+	return prov, nil
+}
@@ -65,3 +65,41 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamFormat(t *testing.T) {
+	dir, err := os.MkdirTemp("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	os.Setenv("HOME", dir)
+	defer os.Unsetenv("HOME")
+
+	err = os.Chdir(dir)
+	assert.NoError(t, err)
+	_, err = git.Init()
+	assert.NoError(t, err)
+
+	err = os.MkdirAll(path.Join(dir, ".plural"), os.ModePerm)
+	assert.NoError(t, err)
+	err = os.WriteFile(path.Join(dir, ".plural", "key"), []byte("key: abc"), 0644)
+	assert.NoError(t, err)
+
+	prov, err := crypto.Build()
+	assert.NoError(t, err)
+	assert.NoError(t, crypto.Flush(prov))
+
+	stream, err := crypto.StreamFormat()
+	assert.NoError(t, err)
+	assert.False(t, stream, "repos keep the legacy format until they opt in")
+
+	contents, err := os.ReadFile(path.Join(dir, "crypto.yml"))
+	assert.NoError(t, err)
+	err = os.WriteFile(path.Join(dir, "crypto.yml"), append(contents, []byte("format: v2\n")...), 0644)
+	assert.NoError(t, err)
+
+	// rewriting the provider config keeps the opt in
+	assert.NoError(t, crypto.Flush(prov))
+	stream, err = crypto.StreamFormat()
+	assert.NoError(t, err)
+	assert.True(t, stream)
+}
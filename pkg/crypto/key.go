@@ -102,6 +102,11 @@ func RandStr(length int) (string, error) {
 	return base64.StdEncoding.EncodeToString(str), nil
 }
 
+// KeyPath is where the plaintext key of repos without a kms is stored.
+func KeyPath() string {
+	return getKeyPath()
+}
+
 func getKeyPath() string {
 	if EncryptionKeyFile != "" {
 		return EncryptionKeyFile
@@ -183,6 +188,15 @@ func GetKeyID() (string, error) {
 }
 
 func CreateKeyFingerprintFile() error {
+	if conf, err := readConfig(); err == nil && conf.Type == KMS {
+		prov, err := buildKMSProvider(conf)
+		if err != nil {
+			return err
+		}
+		kv := KeyValidator{KeyID: prov.ID()}
+		return kv.Flush()
+	}
+
 	aesKey, err := Materialize()
 	if err != nil {
 		return err
@@ -0,0 +1,275 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pluralsh/plural-cli/pkg/config"
+	"github.com/pluralsh/plural-cli/pkg/utils"
+	"github.com/pluralsh/plural-cli/pkg/utils/pathing"
+	"gopkg.in/yaml.v2"
+)
+
+const kmsKeyFile = "kms-key"
+
+var kmsKeys sync.Map
+
+type KMSBackend string
+
+const (
+	AWSKMS        KMSBackend = "aws"
+	GCPKMS        KMSBackend = "gcp"
+	AzureKeyVault KMSBackend = "azure"
+	VaultTransit  KMSBackend = "vault"
+)
+
+// KMSConfig describes the external master key used to wrap the repo data key, and lives in crypto.yml
+type KMSConfig struct {
+	Backend KMSBackend `yaml:"backend" json:"backend"`
+	// Key is the aws key id or arn, the gcp crypto key resource name, or the azure/vault key name
+	Key string `yaml:"key" json:"key"`
+	// Region is the aws region of the key, defaults to the standard aws config chain
+	Region string `yaml:"region,omitempty" json:"region,omitempty"`
+	// Address is the azure key vault url or vault server address, vault defaults to VAULT_ADDR
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	// Mount is the path the vault transit engine is mounted at, defaults to transit
+	Mount string `yaml:"mount,omitempty" json:"mount,omitempty"`
+}
+
+// KMSClient wraps and unwraps a data key with a master key that never leaves the kms
+type KMSClient interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+func NewKMSClient(ctx context.Context, conf *KMSConfig) (KMSClient, error) {
+	if conf.Key == "" {
+		return nil, fmt.Errorf("no key configured for the %s kms backend", conf.Backend)
+	}
+
+	switch conf.Backend {
+	case AWSKMS:
+		return newAWSKMSClient(ctx, conf)
+	case GCPKMS:
+		return newGCPKMSClient(ctx, conf)
+	case AzureKeyVault:
+		return newAzureKeyVaultClient(conf)
+	case VaultTransit:
+		return newVaultTransitClient(conf)
+	}
+
+	return nil, fmt.Errorf("unsupported kms backend %q, must be one of aws, gcp, azure or vault", conf.Backend)
+}
+
+// KMSProvider is an envelope encryption provider, the data key is only ever persisted wrapped by the kms
+type KMSProvider struct {
+	Config *KMSConfig
+	Key    *AESKey
+}
+
+func (prov *KMSProvider) SymmetricKey() ([]byte, error) {
+	dummy := &KeyProvider{key: prov.Key.Key}
+	return dummy.SymmetricKey()
+}
+
+func (prov *KMSProvider) ID() string {
+	dummy := &KeyProvider{key: prov.Key.Key}
+	return dummy.ID()
+}
+
+func (prov *KMSProvider) Marshall() ([]byte, error) {
+	conf := Config{
+		Version: "crypto.plural.sh/v1",
+		Type:    KMS,
+		Id:      prov.ID(),
+		Context: &Context{KMS: prov.Config},
+	}
+
+	return yaml.Marshal(conf)
+}
+
+func buildKMSProvider(conf *Config) (*KMSProvider, error) {
+	if conf.Context == nil || conf.Context.KMS == nil {
+		return nil, fmt.Errorf("crypto.yml is missing the kms configuration")
+	}
+
+	wrapped, err := os.ReadFile(kmsKeyPath())
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := unwrapKMSKey(conf.Context.KMS, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	prov := &KMSProvider{Config: conf.Context.KMS, Key: key}
+	if conf.Id != "" && prov.ID() != conf.Id {
+		return nil, fmt.Errorf("the key fingerprints failed to match")
+	}
+
+	return prov, nil
+}
+
+// SetupKMS wraps the given data key with the configured kms and writes it to the repo, if no key is given
+// a fresh one is generated.  The returned provider must be flushed to persist the configuration.
+func SetupKMS(conf *KMSConfig, key *AESKey) (*KMSProvider, error) {
+	ctx := context.Background()
+	client, err := NewKMSClient(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		str, err := RandStr(32)
+		if err != nil {
+			return nil, err
+		}
+		key = &AESKey{Key: str}
+	}
+
+	keydata, err := key.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := client.Encrypt(ctx, keydata)
+	if err != nil {
+		return nil, fmt.Errorf("could not wrap the repo key with %s kms: %w", conf.Backend, err)
+	}
+
+	if err := os.MkdirAll(cryptPath(), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	encoded := []byte(base64.StdEncoding.EncodeToString(wrapped))
+	if err := os.WriteFile(kmsKeyPath(), encoded, 0644); err != nil {
+		return nil, err
+	}
+	cacheKMSKey(encoded, key)
+
+	if utils.Exists(getKeyValidatorPath()) {
+		kv := KeyValidator{KeyID: key.ID()}
+		if err := kv.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &KMSProvider{Config: conf, Key: key}, nil
+}
+
+// CurrentKey returns the data key of the repo's existing provider, or nil if encryption was never set up
+func CurrentKey() (*AESKey, error) {
+	if !utils.Exists(configPath()) {
+		return nil, nil
+	}
+
+	prov, err := Build()
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := prov.SymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESKey{Key: base64.StdEncoding.EncodeToString(sym)}, nil
+}
+
+// unwrapKMSKey returns the data key wrapped in the repo. Git runs a clean or smudge process per file, so unwrapped
+// keys are cached for the process, and in the user's credential store if one that needs no prompt is configured,
+// keyed by the wrapped key. The unwrapped key is never written to disk in plaintext.
+func unwrapKMSKey(conf *KMSConfig, wrapped []byte) (*AESKey, error) {
+	if key, ok := cachedKMSKey(wrapped); ok {
+		return key, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(string(wrapped))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	client, err := NewKMSClient(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	keydata, err := client.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap the repo key with %s kms: %w", conf.Backend, err)
+	}
+
+	key, err := DeserializeKey(keydata)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKMSKey(wrapped, key)
+	return key, nil
+}
+
+func cachedKMSKey(wrapped []byte) (*AESKey, bool) {
+	id := kmsCacheID(wrapped)
+	if key, ok := kmsKeys.Load(id); ok {
+		return key.(*AESKey), true
+	}
+
+	store, ok := kmsCacheStore()
+	if !ok {
+		return nil, false
+	}
+	contents, err := config.LoadCredential(store, id)
+	if err != nil || contents == "" {
+		return nil, false
+	}
+	key, err := DeserializeKey([]byte(contents))
+	if err != nil || key.Key == "" {
+		return nil, false
+	}
+	kmsKeys.Store(id, key)
+	return key, true
+}
+
+// cacheKMSKey is best effort, a failed write only means the next process unwraps the key again
+func cacheKMSKey(wrapped []byte, key *AESKey) {
+	id := kmsCacheID(wrapped)
+	kmsKeys.Store(id, key)
+
+	store, ok := kmsCacheStore()
+	if !ok {
+		return
+	}
+	contents, err := key.Marshal()
+	if err != nil {
+		return
+	}
+	_ = config.StoreCredential(store, id, string(contents))
+}
+
+// kmsCacheStore returns the credential store unwrapped keys are shared across processes in. Only stores that can
+// be read without prompting qualify, since clean and smudge run without a terminal.
+func kmsCacheStore() (config.CredentialStoreType, bool) {
+	switch store := config.Read().CredentialStore; store {
+	case config.FileStore, config.SecretServiceStore:
+		return store, true
+	}
+	return config.PlaintextStore, false
+}
+
+func kmsCacheID(wrapped []byte) string {
+	sum := sha256.Sum256(bytes.TrimSpace(wrapped))
+	return "kms-" + hex.EncodeToString(sum[:])
+}
+
+func kmsKeyPath() string {
+	return pathing.SanitizeFilepath(filepath.Join(cryptPath(), kmsKeyFile))
+}
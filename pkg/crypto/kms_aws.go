@@ -0,0 +1,52 @@
+package crypto
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+type awsKMSClient struct {
+	client *kms.Client
+	keyID  string
+}
+
+func newAWSKMSClient(ctx context.Context, conf *KMSConfig) (*awsKMSClient, error) {
+	opts := []func(*awsConfig.LoadOptions) error{}
+	if conf.Region != "" {
+		opts = append(opts, awsConfig.WithRegion(conf.Region))
+	}
+
+	cfg, err := awsConfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsKMSClient{client: kms.NewFromConfig(cfg), keyID: conf.Key}, nil
+}
+
+func (c *awsKMSClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	res, err := c.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(c.keyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.CiphertextBlob, nil
+}
+
+func (c *awsKMSClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	res, err := c.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(c.keyID),
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Plaintext, nil
+}
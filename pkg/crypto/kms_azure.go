@@ -0,0 +1,62 @@
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+type azureKeyVaultClient struct {
+	client  *azkeys.Client
+	name    string
+	version string
+}
+
+// newAzureKeyVaultClient expects the key in the form name[/version], pinning a version keeps the wrapped
+// repo key readable after the vault key is rotated
+func newAzureKeyVaultClient(conf *KMSConfig) (*azureKeyVaultClient, error) {
+	if conf.Address == "" {
+		return nil, fmt.Errorf("the azure kms backend requires the key vault url as its address")
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azkeys.NewClient(conf.Address, cred, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name, version, _ := strings.Cut(conf.Key, "/")
+	return &azureKeyVaultClient{client: client, name: name, version: version}, nil
+}
+
+func (c *azureKeyVaultClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	res, err := c.client.WrapKey(ctx, c.name, c.version, azkeys.KeyOperationParameters{
+		Algorithm: to.Ptr(azkeys.EncryptionAlgorithmRSAOAEP256),
+		Value:     plaintext,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
+
+func (c *azureKeyVaultClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	res, err := c.client.UnwrapKey(ctx, c.name, c.version, azkeys.KeyOperationParameters{
+		Algorithm: to.Ptr(azkeys.EncryptionAlgorithmRSAOAEP256),
+		Value:     ciphertext,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Result, nil
+}
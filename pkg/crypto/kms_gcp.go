@@ -0,0 +1,44 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+
+	"google.golang.org/api/cloudkms/v1"
+)
+
+type gcpKMSClient struct {
+	service *cloudkms.Service
+	name    string
+}
+
+func newGCPKMSClient(ctx context.Context, conf *KMSConfig) (*gcpKMSClient, error) {
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcpKMSClient{service: service, name: conf.Key}, nil
+}
+
+func (c *gcpKMSClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	res, err := c.service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(c.name, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(plaintext),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Ciphertext)
+}
+
+func (c *gcpKMSClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	res, err := c.service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(c.name, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Plaintext)
+}
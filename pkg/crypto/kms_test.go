@@ -0,0 +1,104 @@
+package crypto_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/pluralsh/plural-cli/pkg/crypto"
+	"github.com/pluralsh/plural-cli/pkg/utils/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeVaultTransit(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+
+		body := map[string]string{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		data := map[string]string{}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/plural":
+			data["ciphertext"] = "vault:v1:" + body["plaintext"]
+		case "/v1/transit/decrypt/plural":
+			data["plaintext"] = strings.TrimPrefix(body["ciphertext"], "vault:v1:")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": []}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+}
+
+func TestKMSProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		key           *crypto.AESKey
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     `wraps the existing repo key`,
+			token:    "token",
+			key:      &crypto.AESKey{Key: "abc"},
+			expected: "SHA256:XJ4BNP4PAHH6UQKBIDPF3LRCEOYAGYNDSYLXVHFUCD7WD4QACWWQ====",
+		},
+		{
+			name:          `when vault rejects the token`,
+			token:         "invalid",
+			key:           &crypto.AESKey{Key: "abc"},
+			expectedError: "could not wrap the repo key with vault kms: vault transit encrypt: status 403: permission denied",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "config")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			os.Setenv("HOME", dir)
+			defer os.Unsetenv("HOME")
+
+			err = os.Chdir(dir)
+			assert.NoError(t, err)
+			_, err = git.Init()
+			assert.NoError(t, err)
+
+			server := fakeVaultTransit(t)
+			defer server.Close()
+			os.Setenv("VAULT_TOKEN", test.token)
+			defer os.Unsetenv("VAULT_TOKEN")
+
+			conf := &crypto.KMSConfig{Backend: crypto.VaultTransit, Key: "plural", Address: server.URL}
+			prov, err := crypto.SetupKMS(conf, test.key)
+			if test.expectedError != "" {
+				assert.Equal(t, test.expectedError, err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, crypto.Flush(prov))
+
+			built, err := crypto.Build()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, built.ID())
+			assert.NoFileExists(t, path.Join(dir, ".plural", "key"))
+
+			// the unwrapped key is cached in memory, so later builds don't call the kms, and never written to disk
+			server.Close()
+			assert.NoDirExists(t, path.Join(dir, ".plural", "kms"))
+			built, err = crypto.Build()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, built.ID())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type vaultTransitClient struct {
+	client  *http.Client
+	address string
+	mount   string
+	key     string
+	token   string
+}
+
+type vaultTransitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func newVaultTransitClient(conf *KMSConfig) (*vaultTransitClient, error) {
+	address := conf.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return nil, fmt.Errorf("the vault kms backend requires an address or VAULT_ADDR to be set")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("the vault kms backend requires VAULT_TOKEN to be set")
+	}
+
+	mount := conf.Mount
+	if mount == "" {
+		mount = "transit"
+	}
+
+	return &vaultTransitClient{
+		client:  http.DefaultClient,
+		address: strings.TrimSuffix(address, "/"),
+		mount:   strings.Trim(mount, "/"),
+		key:     conf.Key,
+		token:   token,
+	}, nil
+}
+
+func (c *vaultTransitClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	res, err := c.do(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(res.Data.Ciphertext), nil
+}
+
+func (c *vaultTransitClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	res, err := c.do(ctx, "decrypt", map[string]string{"ciphertext": string(ciphertext)})
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+func (c *vaultTransitClient) do(ctx context.Context, op string, body map[string]string) (*vaultTransitResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", c.address, c.mount, op, c.key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	req.Header.Set("Content-Type", "application/json")
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	res := &vaultTransitResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, fmt.Errorf("vault transit %s: unexpected response with status %d", op, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault transit %s: status %d: %s", op, resp.StatusCode, strings.Join(res.Errors, ", "))
+	}
+
+	return res, nil
+}
@@ -1,6 +1,10 @@
 package crypto
 
-import "os"
+import (
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
 
 type IdentityType string
 
@@ -13,6 +17,7 @@ type Provider interface {
 const (
 	KEY IdentityType = "key"
 	AGE IdentityType = "age"
+	KMS IdentityType = "kms"
 )
 
 func Encrypt(prov Provider, text []byte) ([]byte, error) {
@@ -39,5 +44,17 @@ func Flush(prov Provider) error {
 		return err
 	}
 
+	// providers only marshal their own settings, keep the format the repo opted into
+	if existing, err := readConfig(); err == nil && existing.Format != "" {
+		conf := &Config{}
+		if err := yaml.Unmarshal(io, conf); err != nil {
+			return err
+		}
+		conf.Format = existing.Format
+		if io, err = yaml.Marshal(conf); err != nil {
+			return err
+		}
+	}
+
 	return os.WriteFile(configPath(), io, 0644)
 }
@@ -0,0 +1,271 @@
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+const (
+	StreamVersion      = 2
+	StreamAlgorithm    = "AES-256-GCM-HMAC-SHA256"
+	DefaultChunkSize   = 64 * 1024
+	maxChunkSize       = 16 * 1024 * 1024
+	maxStreamHeaderLen = 4096
+)
+
+// Prefix marks a file as encrypted by plural, it is shared by every format version
+var Prefix = []byte("CHARTMART-ENCRYPTED")
+
+// streamMarker follows the prefix to distinguish the v2 format from legacy ciphertext, which starts with a raw nonce
+var streamMarker = []byte("\x00PLURAL-V2\x00")
+
+var errTruncated = errors.New("encrypted stream is truncated")
+
+// StreamHeader is written in the clear at the start of every v2 file and authenticated alongside each chunk
+type StreamHeader struct {
+	Version   int    `json:"version"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	ChunkSize int    `json:"chunk"`
+	Path      string `json:"path,omitempty"`
+}
+
+// IsEncrypted reports whether the head of a file carries the plural encryption prefix, in any format
+func IsEncrypted(head []byte) bool {
+	return bytes.HasPrefix(head, Prefix)
+}
+
+// IsStreamEncrypted reports whether the head of a file is in the v2 streaming format, callers should peek at
+// least StreamHeadLen bytes
+func IsStreamEncrypted(head []byte) bool {
+	return bytes.HasPrefix(head, append(append([]byte{}, Prefix...), streamMarker...))
+}
+
+// StreamHeadLen is the number of bytes needed to sniff the encryption format of a file
+func StreamHeadLen() int {
+	return len(Prefix) + len(streamMarker)
+}
+
+type streamCipher struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+	header   []byte
+	path     string
+}
+
+func newStreamCipher(prov Provider, header []byte, path string) (*streamCipher, error) {
+	key, err := prov.SymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(deriveKey(key, "plural-crypt-v2-encryption"))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(header)
+	return &streamCipher{
+		aead:     gcm,
+		nonceKey: deriveKey(key, "plural-crypt-v2-nonce"),
+		header:   sum[:],
+		path:     path,
+	}, nil
+}
+
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// associatedData binds each chunk to the header, the file path, its position and whether it ends the stream,
+// so chunks cannot be reordered, truncated or moved between files without failing authentication
+func (s *streamCipher) associatedData(index uint64, final bool) []byte {
+	ad := make([]byte, 0, len(s.header)+len(s.path)+9)
+	ad = append(ad, s.header...)
+	ad = append(ad, s.path...)
+	ad = binary.BigEndian.AppendUint64(ad, index)
+	if final {
+		return append(ad, 1)
+	}
+	return append(ad, 0)
+}
+
+// seal derives the nonce from the chunk contents, keeping encryption deterministic so git clean filters
+// produce stable output for unchanged files
+func (s *streamCipher) seal(dst, chunk []byte, index uint64, final bool) []byte {
+	ad := s.associatedData(index, final)
+	mac := hmac.New(sha256.New, s.nonceKey)
+	mac.Write(ad)
+	mac.Write(chunk)
+	nonce := mac.Sum(nil)[:s.aead.NonceSize()]
+	dst = append(dst, nonce...)
+	return s.aead.Seal(dst, nonce, chunk, ad)
+}
+
+func (s *streamCipher) open(dst, chunk []byte, index uint64, final bool) ([]byte, error) {
+	if len(chunk) < s.aead.NonceSize()+s.aead.Overhead() {
+		return nil, errTruncated
+	}
+	nonce := chunk[:s.aead.NonceSize()]
+	return s.aead.Open(dst, nonce, chunk[s.aead.NonceSize():], s.associatedData(index, final))
+}
+
+// EncryptStream encrypts r into w in the v2 format, reading at most one chunk at a time.  The path is bound
+// into every chunk as associated data and should be the repo relative path of the file, if known.
+func EncryptStream(prov Provider, path string, r io.Reader, w io.Writer) error {
+	header, err := json.Marshal(StreamHeader{
+		Version:   StreamVersion,
+		KeyID:     prov.ID(),
+		Algorithm: StreamAlgorithm,
+		ChunkSize: DefaultChunkSize,
+		Path:      path,
+	})
+	if err != nil {
+		return err
+	}
+
+	s, err := newStreamCipher(prov, header, path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(Prefix); err != nil {
+		return err
+	}
+	if _, err := w.Write(streamMarker); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(header))); err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	in := bufio.NewReaderSize(r, DefaultChunkSize)
+	chunk := make([]byte, DefaultChunkSize)
+	out := make([]byte, 0, DefaultChunkSize+s.aead.NonceSize()+s.aead.Overhead())
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(in, chunk)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+
+		final := err != nil
+		if !final {
+			_, peekErr := in.Peek(1)
+			final = errors.Is(peekErr, io.EOF)
+		}
+
+		if _, err := w.Write(s.seal(out[:0], chunk[:n], index, final)); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// ReadStreamHeader reads the v2 header from r, which must be positioned at the start of the file
+func ReadStreamHeader(r io.Reader) (*StreamHeader, []byte, error) {
+	head := make([]byte, StreamHeadLen())
+	if _, err := io.ReadFull(r, head); err != nil || !IsStreamEncrypted(head) {
+		return nil, nil, fmt.Errorf("not a v2 encrypted file")
+	}
+
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, nil, errTruncated
+	}
+	if length > maxStreamHeaderLen {
+		return nil, nil, fmt.Errorf("encrypted file header is too large")
+	}
+
+	raw := make([]byte, length)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, nil, errTruncated
+	}
+
+	header := &StreamHeader{}
+	if err := json.Unmarshal(raw, header); err != nil {
+		return nil, nil, fmt.Errorf("malformed encrypted file header: %w", err)
+	}
+	return header, raw, nil
+}
+
+// DecryptStream decrypts a v2 file from r into w, only writing chunks once they have been authenticated.  Chunks are
+// authenticated against the path bound at encryption time, so a tampered header fails.  If path is set and differs
+// from it, the file was renamed or copied since it was added, which is only warned about so renamed files can still
+// be checked out, re-adding the file binds it to its new path.
+func DecryptStream(prov Provider, path string, r io.Reader, w io.Writer) (*StreamHeader, error) {
+	header, raw, err := ReadStreamHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Version != StreamVersion || header.Algorithm != StreamAlgorithm {
+		return header, fmt.Errorf("unsupported encryption format %d with algorithm %s, try upgrading the plural cli", header.Version, header.Algorithm)
+	}
+	if header.ChunkSize <= 0 || header.ChunkSize > maxChunkSize {
+		return header, fmt.Errorf("invalid chunk size %d in encrypted file header", header.ChunkSize)
+	}
+	if header.KeyID != prov.ID() {
+		return header, fmt.Errorf("file was encrypted with key %s, but the current key is %s", header.KeyID, prov.ID())
+	}
+
+	if path != "" && header.Path != "" && path != header.Path {
+		utils.Warn("%s was encrypted for %s, run `git add --renormalize %s` if it was moved\n", path, header.Path, path)
+	}
+
+	s, err := newStreamCipher(prov, raw, header.Path)
+	if err != nil {
+		return header, err
+	}
+
+	in := bufio.NewReaderSize(r, header.ChunkSize)
+	chunk := make([]byte, header.ChunkSize+s.aead.NonceSize()+s.aead.Overhead())
+	out := make([]byte, 0, header.ChunkSize)
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(in, chunk)
+		if errors.Is(err, io.EOF) {
+			return header, errTruncated
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return header, err
+		}
+
+		final := err != nil
+		if !final {
+			_, peekErr := in.Peek(1)
+			final = errors.Is(peekErr, io.EOF)
+		}
+
+		plain, err := s.open(out[:0], chunk[:n], index, final)
+		if err != nil {
+			return header, fmt.Errorf("could not authenticate chunk %d of encrypted file: %w", index, err)
+		}
+		if _, err := w.Write(plain); err != nil {
+			return header, err
+		}
+		if final {
+			return header, nil
+		}
+	}
+}
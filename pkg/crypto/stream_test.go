@@ -0,0 +1,120 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/pluralsh/plural-cli/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticProvider struct {
+	id  string
+	key []byte
+}
+
+func (p *staticProvider) ID() string                    { return p.id }
+func (p *staticProvider) SymmetricKey() ([]byte, error) { return p.key, nil }
+func (p *staticProvider) Marshall() ([]byte, error)     { return nil, nil }
+
+func TestStreamRoundTrip(t *testing.T) {
+	prov := &staticProvider{id: "test", key: bytes.Repeat([]byte{1}, 32)}
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: `empty file`, size: 0},
+		{name: `small file`, size: 100},
+		{name: `exactly one chunk`, size: crypto.DefaultChunkSize},
+		{name: `multiple chunks`, size: 2*crypto.DefaultChunkSize + 5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plain := make([]byte, test.size)
+			_, err := rand.Read(plain)
+			assert.NoError(t, err)
+
+			var encrypted bytes.Buffer
+			err = crypto.EncryptStream(prov, "helm-values/app.yaml", bytes.NewReader(plain), &encrypted)
+			assert.NoError(t, err)
+			assert.True(t, crypto.IsStreamEncrypted(encrypted.Bytes()))
+
+			var again bytes.Buffer
+			err = crypto.EncryptStream(prov, "helm-values/app.yaml", bytes.NewReader(plain), &again)
+			assert.NoError(t, err)
+			assert.Equal(t, encrypted.Bytes(), again.Bytes(), "encryption must be deterministic for git filters")
+
+			var decrypted bytes.Buffer
+			header, err := crypto.DecryptStream(prov, "helm-values/app.yaml", bytes.NewReader(encrypted.Bytes()), &decrypted)
+			assert.NoError(t, err)
+			assert.Equal(t, "helm-values/app.yaml", header.Path)
+			assert.True(t, bytes.Equal(plain, decrypted.Bytes()))
+		})
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	prov := &staticProvider{id: "test", key: bytes.Repeat([]byte{1}, 32)}
+	plain := bytes.Repeat([]byte("secret"), crypto.DefaultChunkSize/2)
+
+	var buf bytes.Buffer
+	err := crypto.EncryptStream(prov, "context.yaml", bytes.NewReader(plain), &buf)
+	assert.NoError(t, err)
+	encrypted := buf.Bytes()
+
+	tests := []struct {
+		name      string
+		prov      crypto.Provider
+		path      string
+		encrypted []byte
+		expected  string
+	}{
+		{
+			name:      `when a byte is flipped`,
+			prov:      prov,
+			encrypted: flip(encrypted, len(encrypted)-1),
+			expected:  "could not authenticate chunk 2 of encrypted file: cipher: message authentication failed",
+		},
+		{
+			name:      `when the last chunk is dropped`,
+			prov:      prov,
+			encrypted: encrypted[:len(encrypted)-(len(plain)-2*crypto.DefaultChunkSize)-28],
+			expected:  "could not authenticate chunk 1 of encrypted file: cipher: message authentication failed",
+		},
+		{
+			name:      `when the key does not match`,
+			prov:      &staticProvider{id: "other", key: bytes.Repeat([]byte{2}, 32)},
+			encrypted: encrypted,
+			expected:  "file was encrypted with key test, but the current key is other",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			_, err := crypto.DecryptStream(test.prov, test.path, bytes.NewReader(test.encrypted), &out)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecryptStreamRenamedFile(t *testing.T) {
+	prov := &staticProvider{id: "test", key: bytes.Repeat([]byte{1}, 32)}
+	plain := []byte("password: hunter2\n")
+
+	var buf bytes.Buffer
+	assert.NoError(t, crypto.EncryptStream(prov, "helm-values/old.yaml", bytes.NewReader(plain), &buf))
+
+	// after a git mv the committed blob still carries the old path, smudge must still check it out
+	var out bytes.Buffer
+	header, err := crypto.DecryptStream(prov, "helm-values/new.yaml", bytes.NewReader(buf.Bytes()), &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "helm-values/old.yaml", header.Path)
+	assert.Equal(t, plain, out.Bytes())
+}
+
+func flip(data []byte, ind int) []byte {
+	res := append([]byte{}, data...)
+	res[ind] ^= 0xff
+	return res
+}
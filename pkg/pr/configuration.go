@@ -0,0 +1,43 @@
+package pr
+
+import (
+	console "github.com/pluralsh/console/go/client"
+	"github.com/samber/lo"
+
+	"github.com/pluralsh/plural-cli/pkg/api"
+	"github.com/pluralsh/plural-cli/pkg/bundle"
+)
+
+// ValidateAutomationConfiguration checks values against the configuration of a PR automation fetched from
+// the console, so an invalid configuration fails locally instead of opening a broken pull request.
+func ValidateAutomationConfiguration(automation *console.PrAutomationFragment, values map[string]interface{}) (map[string]interface{}, error) {
+	items := make([]*api.ConfigurationItem, 0, len(automation.Configuration))
+	for _, conf := range automation.Configuration {
+		if conf == nil {
+			continue
+		}
+
+		item := &api.ConfigurationItem{
+			Name:          conf.Name,
+			Type:          string(conf.Type),
+			Default:       lo.FromPtr(conf.Default),
+			Documentation: lo.FromPtr(conf.Documentation),
+			Placeholder:   lo.FromPtr(conf.Placeholder),
+			Optional:      lo.FromPtr(conf.Optional),
+			Values:        lo.Map(conf.Values, func(v *string, _ int) string { return lo.FromPtr(v) }),
+		}
+		if conf.Condition != nil {
+			item.Condition = &api.Condition{
+				Field:     conf.Condition.Field,
+				Operation: string(conf.Condition.Operation),
+				Value:     lo.FromPtr(conf.Condition.Value),
+			}
+		}
+		if conf.Validation != nil && conf.Validation.Regex != nil {
+			item.Validation = &api.Validation{Regex: *conf.Validation.Regex, Message: lo.FromPtr(conf.Validation.Message)}
+		}
+		items = append(items, item)
+	}
+
+	return bundle.ValidateConfiguration(items, values)
+}
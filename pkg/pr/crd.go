@@ -18,14 +18,84 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// CRDOptions configures how BuildCRD resolves a PrAutomation.
+type CRDOptions struct {
+	// Context is a yaml file holding the configuration.
+	Context string
+	// Configuration values take precedence over Context. When either is set the configuration is
+	// validated without prompting, otherwise the user is prompted for every item.
+	Configuration map[string]interface{}
+	// Lenient loads Context as is without validating it against the configuration items, the way contract
+	// suites written before validation expect partial contexts to be accepted.
+	Lenient bool
+	// ExternalDir is a local checkout of creates.git used instead of fetching it, eg for offline tests.
+	ExternalDir string
+	// RepositoryURL is fetched for creates.git when the file has no GitRepository matching spec.repositoryRef.
+	RepositoryURL string
+}
+
 func BuildCRD(path, contextFile string) (*PrTemplate, error) {
-	var prAutomationOk bool
-	pr := &v1alpha1.PrAutomation{}
-	data, err := os.ReadFile(path)
+	return BuildCRDWithOptions(path, CRDOptions{Context: contextFile})
+}
+
+// BuildCRDWithOptions maps every PrAutomation spec field onto a PrTemplate, fetching the creates.git
+// repository if any template or the lua folder is external. Spec fields that can't be applied locally
+// are printed as warnings and recorded in the template's Warnings.
+func BuildCRDWithOptions(path string, opts CRDOptions) (*PrTemplate, error) {
+	pr, raw, docs, err := readPrAutomation(path)
+	if err != nil {
+		return nil, err
+	}
+
+	prTemplate := &PrTemplate{
+		ApiVersion: pr.APIVersion,
+		Kind:       pr.Kind,
+		Metadata:   map[string]interface{}{"name": pr.Name},
+		Spec:       PrTemplateSpec{},
+	}
+
+	if spec, ok := raw["spec"].(map[string]interface{}); ok {
+		prTemplate.Warnings = unsupportedFields(spec)
+	}
+	for _, field := range prTemplate.Warnings {
+		utils.Warn("WARNING: %s is not supported locally and will be ignored\n", field)
+	}
+
+	ctx, err := configuration(pr, opts)
 	if err != nil {
 		return nil, err
 	}
+	prTemplate.Context = ctx
+
+	if prTemplate.Spec.Lua, err = luaSpec(raw); err != nil {
+		return nil, err
+	}
+	if prTemplate.Spec.Vendor, err = vendorSpec(raw); err != nil {
+		return nil, err
+	}
+	prTemplate.Spec.Creates = creates(pr)
+	prTemplate.Spec.Updates = updates(pr)
+	prTemplate.Spec.Deletes = deletes(pr)
+
+	if err := resolveExternal(prTemplate, pr, raw, docs, opts); err != nil {
+		prTemplate.Cleanup()
+		return nil, err
+	}
+
+	return prTemplate, nil
+}
+
+// readPrAutomation returns the first PrAutomation in a multi document yaml file, both decoded and raw,
+// along with every document of the file
+func readPrAutomation(path string) (*v1alpha1.PrAutomation, map[string]interface{}, []map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
+	var raw map[string]interface{}
+	pr := &v1alpha1.PrAutomation{}
+	docs := make([]map[string]interface{}, 0)
 	yamlDocs := strings.Split(string(data), "---")
 	for _, yamlDoc := range yamlDocs {
 		// Skip empty documents (may occur if there are extra `---`)
@@ -33,46 +103,102 @@ func BuildCRD(path, contextFile string) (*PrTemplate, error) {
 			continue
 		}
 
-		if err := yaml.Unmarshal([]byte(yamlDoc), pr); err != nil {
-			return nil, err
+		doc := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(yamlDoc), &doc); err != nil {
+			return nil, nil, nil, err
 		}
-		if prAutomationOk = isPrAutomation(pr); prAutomationOk {
-			break
+		docs = append(docs, doc)
+		if raw != nil {
+			continue
 		}
-	}
 
-	if !prAutomationOk {
-		return nil, fmt.Errorf("no pr automation found in %s", path)
+		candidate := &v1alpha1.PrAutomation{}
+		if err := yaml.Unmarshal([]byte(yamlDoc), candidate); err != nil {
+			return nil, nil, nil, err
+		}
+		if isPrAutomation(candidate) {
+			pr, raw = candidate, doc
+		}
 	}
 
-	prTemplate := &PrTemplate{
-		ApiVersion: pr.APIVersion,
-		Kind:       pr.Kind,
-		Spec:       PrTemplateSpec{},
+	if raw == nil {
+		return nil, nil, nil, fmt.Errorf("no pr automation found in %s", path)
 	}
+	return pr, raw, docs, nil
+}
 
-	ctx, err := configuration(pr, contextFile)
-	if err != nil {
-		return nil, err
+// resolveExternal points external templates and lua folders at the creates.git folder, fetching it unless
+// a local checkout was passed in
+func resolveExternal(prTemplate *PrTemplate, pr *v1alpha1.PrAutomation, raw map[string]interface{}, docs []map[string]interface{}, opts CRDOptions) error {
+	spec := &prTemplate.Spec
+	external := spec.Lua != nil && spec.Lua.External
+	if spec.Creates != nil {
+		external = external || lo.SomeBy(spec.Creates.Templates, func(t *CreateTemplate) bool { return t.External })
+	}
+	if !external && opts.ExternalDir == "" {
+		return nil
 	}
-	prTemplate.Context = ctx
 
-	prTemplate.Spec.Creates = creates(pr)
-	prTemplate.Spec.Updates = updates(pr)
-	prTemplate.Spec.Deletes = deletes(pr)
+	dir := opts.ExternalDir
+	if dir == "" {
+		if pr.Spec.Creates == nil || pr.Spec.Creates.Git == nil {
+			return fmt.Errorf("external templates need creates.git to be set")
+		}
+		gitRef := pr.Spec.Creates.Git
 
-	return prTemplate, nil
+		url, err := gitRepositoryURL(raw, docs)
+		if err != nil {
+			return err
+		}
+		if url == "" {
+			url = opts.RepositoryURL
+		}
+
+		folder, cleanup, err := fetchGitFolder(url, gitRef.Ref, gitRef.Folder)
+		if err != nil {
+			return err
+		}
+		prTemplate.cleanups = append(prTemplate.cleanups, cleanup)
+		dir = folder
+	}
+
+	if spec.Creates != nil {
+		spec.Creates.ExternalDir = dir
+	}
+	if spec.Lua != nil && spec.Lua.External {
+		spec.Lua.ExternalDir = dir
+	}
+	return nil
 }
 
-func configuration(pr *v1alpha1.PrAutomation, contextFile string) (map[string]interface{}, error) {
-	ctx := map[string]interface{}{}
-	if len(pr.Spec.Configuration) == 0 {
-		return ctx, nil
+func configuration(pr *v1alpha1.PrAutomation, opts CRDOptions) (map[string]interface{}, error) {
+	items := configurationItems(pr)
+	if opts.Lenient && opts.Context != "" {
+		values := map[string]interface{}{}
+		if err := utils.YamlFile(opts.Context, &values); err != nil {
+			return nil, err
+		}
+		for name, value := range opts.Configuration {
+			values[name] = value
+		}
+		return values, nil
+	}
+	if opts.Context != "" || len(opts.Configuration) > 0 {
+		values := map[string]interface{}{}
+		if opts.Context != "" {
+			if err := utils.YamlFile(opts.Context, &values); err != nil {
+				return nil, err
+			}
+		}
+		for name, value := range opts.Configuration {
+			values[name] = value
+		}
+		return bundle.ValidateConfiguration(items, values)
 	}
 
-	if contextFile != "" {
-		err := utils.YamlFile(contextFile, &ctx)
-		return ctx, err
+	ctx := map[string]interface{}{}
+	if len(items) == 0 {
+		return ctx, nil
 	}
 
 	path := manifest.ProjectManifestPath()
@@ -84,7 +210,17 @@ func configuration(pr *v1alpha1.PrAutomation, contextFile string) (map[string]in
 		defer os.Remove(path)
 	}
 
-	items := algorithms.Map(pr.Spec.Configuration, func(t v1alpha1.PrAutomationConfiguration) *api.ConfigurationItem {
+	utils.Highlight("Lets' fill out the configuration for this PR automation:\n")
+	for _, item := range items {
+		if err := bundle.Configure(ctx, item, &manifest.Context{}, ""); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+func configurationItems(pr *v1alpha1.PrAutomation) []*api.ConfigurationItem {
+	return algorithms.Map(pr.Spec.Configuration, func(t v1alpha1.PrAutomationConfiguration) *api.ConfigurationItem {
 		ci := &api.ConfigurationItem{
 			Name:       t.Name,
 			Type:       t.Type.String(),
@@ -126,13 +262,6 @@ func configuration(pr *v1alpha1.PrAutomation, contextFile string) (map[string]in
 		}
 		return ci
 	})
-	utils.Highlight("Lets' fill out the configuration for this PR automation:\n")
-	for _, item := range items {
-		if err := bundle.Configure(ctx, item, &manifest.Context{}, ""); err != nil {
-			return ctx, err
-		}
-	}
-	return ctx, nil
 }
 
 func deletes(pr *v1alpha1.PrAutomation) *DeleteSpec {
@@ -207,9 +336,6 @@ func creates(pr *v1alpha1.PrAutomation) *CreateSpec {
 	prCreates := &CreateSpec{
 		Templates: make([]*CreateTemplate, 0),
 	}
-	if c.Git != nil {
-		prCreates.ExternalDir = c.Git.Folder
-	}
 	for _, t := range c.Templates {
 		createTemplate := &CreateTemplate{
 			Source:      t.Source,
@@ -0,0 +1,190 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pluralsh/plural-cli/pkg/utils"
+	"github.com/pluralsh/plural-cli/pkg/utils/git"
+)
+
+// fieldSet describes the fields of an object, nested sets describe the fields of child objects or of
+// the items of child lists, nil values are leaves
+type fieldSet map[string]fieldSet
+
+// crdFields are the PrAutomation spec fields BuildCRD maps onto a PrTemplate
+var crdFields = fieldSet{
+	"creates": {
+		"git": {"ref": nil, "folder": nil},
+		"templates": {
+			"source":      nil,
+			"destination": nil,
+			"external":    nil,
+			"context":     nil,
+			"condition":   nil,
+		},
+	},
+	"updates": {
+		"regexes":         nil,
+		"files":           nil,
+		"replaceTemplate": nil,
+		"yq":              nil,
+		"matchStrategy":   nil,
+		"regexReplacements": {
+			"regex":       nil,
+			"replacement": nil,
+			"file":        nil,
+			"templated":   nil,
+		},
+		"yamlOverlays": {
+			"file":      nil,
+			"yaml":      nil,
+			"templated": nil,
+			"listMerge": nil,
+		},
+	},
+	"deletes": {"files": nil, "folders": nil},
+	"lua":     {"script": nil, "folder": nil, "external": nil},
+	"vendor": {
+		"helm": {"url": nil, "chart": nil, "version": nil, "destination": nil},
+	},
+	"configuration": {
+		"name":          nil,
+		"type":          nil,
+		"default":       nil,
+		"documentation": nil,
+		"placeholder":   nil,
+		"optional":      nil,
+		"values":        nil,
+		"condition":     {"field": nil, "operation": nil, "value": nil},
+		"validation":    {"regex": nil},
+	},
+}
+
+// crdMetadataFields only affect how the console opens the pull request and never change its files,
+// so they are accepted without a warning
+var crdMetadataFields = []string{
+	"name", "title", "message", "branch", "documentation", "identifier", "icon", "darkIcon", "addon",
+	"patch", "role", "labels", "confirmation", "secrets", "reconciliation", "repositoryRef", "clusterRef",
+	"serviceRef", "scmConnectionRef", "projectRef", "catalogRef", "governanceRef",
+}
+
+// unsupportedFields returns the dotted paths of every spec field that BuildCRD would silently drop
+func unsupportedFields(spec map[string]interface{}) []string {
+	fields := fieldSet{}
+	for name, set := range crdFields {
+		fields[name] = set
+	}
+	for _, name := range crdMetadataFields {
+		fields[name] = nil
+	}
+
+	unsupported := collectUnsupported("spec", spec, fields)
+	sort.Strings(unsupported)
+	return unsupported
+}
+
+func collectUnsupported(path string, value interface{}, fields fieldSet) []string {
+	unsupported := make([]string, 0)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			set, ok := fields[name]
+			if !ok {
+				unsupported = append(unsupported, path+"."+name)
+				continue
+			}
+			if set != nil {
+				unsupported = append(unsupported, collectUnsupported(path+"."+name, child, set)...)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			unsupported = append(unsupported, collectUnsupported(fmt.Sprintf("%s[%d]", path, i), item, fields)...)
+		}
+	}
+	return unsupported
+}
+
+// rawField decodes the field at path in a yaml document into out, returning false if it isn't set
+func rawField(doc map[string]interface{}, out interface{}, path ...string) (bool, error) {
+	var value interface{} = doc
+	for _, name := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		if value, ok = m[name]; !ok || value == nil {
+			return false, nil
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("invalid %s: %w", strings.Join(path, "."), err)
+	}
+	return true, nil
+}
+
+func luaSpec(doc map[string]interface{}) (*LuaSpec, error) {
+	lua := &LuaSpec{}
+	if ok, err := rawField(doc, lua, "spec", "lua"); !ok || err != nil {
+		return nil, err
+	}
+	return lua, nil
+}
+
+func vendorSpec(doc map[string]interface{}) (*VendorSpec, error) {
+	vendor := &VendorSpec{}
+	if ok, err := rawField(doc, vendor, "spec", "vendor"); !ok || err != nil {
+		return nil, err
+	}
+	return vendor, nil
+}
+
+// gitRepositoryURL finds the url of the GitRepository named by the automation's repositoryRef
+// among the other documents of the file
+func gitRepositoryURL(pr map[string]interface{}, docs []map[string]interface{}) (string, error) {
+	var ref struct {
+		Name string `json:"name"`
+	}
+	if ok, err := rawField(pr, &ref, "spec", "repositoryRef"); !ok || err != nil {
+		return "", err
+	}
+
+	for _, doc := range docs {
+		var kind, name, url string
+		_, _ = rawField(doc, &kind, "kind")
+		_, _ = rawField(doc, &name, "metadata", "name")
+		if kind != "GitRepository" || name != ref.Name {
+			continue
+		}
+		if _, err := rawField(doc, &url, "spec", "url"); err != nil {
+			return "", err
+		}
+		return url, nil
+	}
+	return "", nil
+}
+
+// fetchGitFolder shallow clones ref of url, returning the path of folder within it and a cleanup removing the clone
+func fetchGitFolder(url, ref, folder string) (string, func(), error) {
+	if url == "" {
+		return "", nil, fmt.Errorf("creates.git needs a repository, add the GitRepository referenced by spec.repositoryRef to the file or pass a local checkout with --templates")
+	}
+	if ref == "" {
+		return "", nil, fmt.Errorf("creates.git.ref must be set")
+	}
+
+	utils.Highlight("Fetching %s at %s\n", url, ref)
+	path, cleanup, err := git.FetchFolder(url, ref, folder)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch %s at %s: %w", url, ref, err)
+	}
+	return path, cleanup, nil
+}
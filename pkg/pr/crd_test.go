@@ -2,10 +2,13 @@ package pr_test
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/pluralsh/plural-cli/pkg/pr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildCRD(t *testing.T) {
@@ -43,3 +46,71 @@ func TestBuildCRD(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildCRDFullSpec(t *testing.T) {
+	templates := t.TempDir()
+	prTemplate, err := pr.BuildCRDWithOptions("../../test/prautomation/full.yaml", pr.CRDOptions{ExternalDir: templates})
+	require.NoError(t, err)
+
+	assert.Equal(t, &pr.LuaSpec{ExternalDir: templates, External: true, Folder: "lua", Script: "prAutomation = {}\n"}, prTemplate.Spec.Lua)
+	assert.Equal(t, &pr.VendorSpec{Helm: &pr.Helm{
+		URL:         "https://pluralsh.github.io/console",
+		Chart:       "console",
+		Version:     "0.3.0",
+		Destination: "charts",
+	}}, prTemplate.Spec.Vendor)
+	assert.Equal(t, templates, prTemplate.Spec.Creates.ExternalDir)
+	assert.Equal(t, []string{"spec.sparkles", "spec.updates.regexReplacements[0].unknownOption"}, prTemplate.Warnings)
+}
+
+func TestBuildCRDFetchesCreatesGit(t *testing.T) {
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "setup", "blob"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "setup", "blob", "stack.yaml"), []byte("name: blob"), 0644))
+	for _, args := range [][]string{
+		{"init", "--quiet", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@plural.sh", "commit", "--quiet", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	path := filepath.Join(t.TempDir(), "pra.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`apiVersion: deployments.plural.sh/v1alpha1
+kind: PrAutomation
+metadata:
+  name: git
+spec:
+  creates:
+    git:
+      ref: main
+      folder: setup
+    templates:
+      - source: blob/stack.yaml
+        destination: blob.yaml
+        external: true
+`), 0644))
+
+	prTemplate, err := pr.BuildCRDWithOptions(path, pr.CRDOptions{RepositoryURL: repo})
+	require.NoError(t, err)
+	assert.Empty(t, prTemplate.Warnings)
+	assert.FileExists(t, filepath.Join(prTemplate.Spec.Creates.ExternalDir, "blob", "stack.yaml"))
+
+	prTemplate.Cleanup()
+	assert.NoDirExists(t, prTemplate.Spec.Creates.ExternalDir)
+}
+
+func TestBuildCRDLenientContext(t *testing.T) {
+	context := filepath.Join(t.TempDir(), "context.yaml")
+	require.NoError(t, os.WriteFile(context, []byte("name: test\n"), 0644))
+
+	_, err := pr.BuildCRDWithOptions("../../test/prautomation/prautomations.yaml", pr.CRDOptions{Context: context})
+	assert.ErrorContains(t, err, "a value is required")
+
+	prTemplate, err := pr.BuildCRDWithOptions("../../test/prautomation/prautomations.yaml", pr.CRDOptions{Context: context, Lenient: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "test"}, prTemplate.Context)
+}
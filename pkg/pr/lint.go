@@ -0,0 +1,107 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pluralsh/plural-cli/pkg/api"
+	"github.com/pluralsh/plural-cli/pkg/bundle"
+	"github.com/pluralsh/plural-cli/pkg/condition"
+)
+
+type LintResult struct {
+	// Errors are conditions that can't be parsed or can never be evaluated correctly
+	Errors []string
+	// Warnings are spec fields that are ignored locally
+	Warnings []string
+}
+
+// Lint type checks every condition of a PrAutomation. Configuration conditions may only reference the
+// items configured before them, create template conditions may reference any item and the template context.
+func Lint(path string) (*LintResult, error) {
+	pr, raw, _, err := readPrAutomation(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LintResult{Errors: make([]string, 0), Warnings: make([]string, 0)}
+	if spec, ok := raw["spec"].(map[string]interface{}); ok {
+		for _, field := range unsupportedFields(spec) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s is not supported locally and will be ignored", field))
+		}
+	}
+
+	fields := map[string]condition.Field{}
+	for _, item := range configurationItems(pr) {
+		for _, err := range lintItem(item, fields) {
+			result.Errors = append(result.Errors, fmt.Sprintf("configuration %s: %s", item.Name, err))
+		}
+		fields[item.Name] = conditionField(item)
+	}
+
+	if creates := creates(pr); creates != nil {
+		for i, tpl := range creates.Templates {
+			templateFields := map[string]condition.Field{}
+			for name, field := range fields {
+				templateFields[name] = field
+			}
+			for name := range tpl.Context {
+				templateFields[name] = condition.Field{Kind: condition.KindAny}
+			}
+
+			expr, err := condition.Parse(tpl.Condition)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("creates.templates[%d]: %s", i, err))
+				continue
+			}
+			for _, err := range expr.Check(templateFields) {
+				result.Errors = append(result.Errors, fmt.Sprintf("creates.templates[%d]: %s", i, err))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func lintItem(item *api.ConfigurationItem, fields map[string]condition.Field) []error {
+	errs := make([]error, 0)
+	if item.Type == bundle.Enum && len(item.Values) == 0 {
+		errs = append(errs, fmt.Errorf("enum has no values"))
+	}
+	if item.Validation != nil && item.Validation.Regex != "" {
+		if _, err := regexp.Compile(item.Validation.Regex); err != nil {
+			errs = append(errs, fmt.Errorf("invalid validation regex %q: %w", item.Validation.Regex, err))
+		}
+	}
+	if item.Default != "" {
+		// the condition is checked on its own below
+		unconditional := *item
+		unconditional.Condition = nil
+		if _, err := bundle.ValidateConfiguration([]*api.ConfigurationItem{&unconditional}, map[string]interface{}{item.Name: item.Default}); err != nil {
+			errs = append(errs, fmt.Errorf("default %q is invalid", item.Default))
+		}
+	}
+
+	if item.Condition == nil {
+		return errs
+	}
+	expr, err := condition.FromCondition(item.Condition.Field, item.Condition.Operation, item.Condition.Value)
+	if err != nil {
+		return append(errs, err)
+	}
+	return append(errs, expr.Check(fields)...)
+}
+
+func conditionField(item *api.ConfigurationItem) condition.Field {
+	switch item.Type {
+	case bundle.Int:
+		return condition.Field{Kind: condition.KindInt}
+	case bundle.Bool:
+		return condition.Field{Kind: condition.KindBool}
+	case bundle.Enum:
+		return condition.Field{Kind: condition.KindString, Values: item.Values}
+	case bundle.Function:
+		return condition.Field{Kind: condition.KindAny}
+	}
+	return condition.Field{Kind: condition.KindString}
+}
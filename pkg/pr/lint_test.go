@@ -0,0 +1,24 @@
+package pr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/pr"
+)
+
+func TestLint(t *testing.T) {
+	result, err := pr.Lint("../../test/prautomation/lint.yaml")
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, []string{
+		`configuration replicas: default "two" is invalid`,
+		`configuration replicas: condition "type EQ \"azure\"": context.type is never "azure", it must be one of s3, gcs`,
+		`configuration ha: condition "region GT \"1\"": context.region references unknown field region`,
+		`creates.templates[1]: condition "context.ha == \"yes\" or context.zone == \"a\"": context.ha is a bool and can't equal "yes"`,
+		`creates.templates[2]: invalid condition "context.type ==": expected a value or selector at position 15, found "end of expression"`,
+	}, result.Errors)
+}
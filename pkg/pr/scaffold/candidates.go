@@ -0,0 +1,102 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Candidate is a scalar of an example yaml file that could become a configuration variable.
+type Candidate struct {
+	Literal string
+	// Key is the yaml key the literal was found under, used to suggest a variable name
+	Key string
+}
+
+// skippedKeys hold values that describe the shape of a document rather than its configuration
+var skippedKeys = map[string]bool{"apiVersion": true, "kind": true}
+
+var nonIdentifier = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// Candidates lists the distinct scalars of the yaml files among sources, file by file with keys sorted.
+func Candidates(sources []string) ([]Candidate, error) {
+	files, err := sourceFiles(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]Candidate, 0)
+	seen := map[string]bool{}
+	for _, f := range files {
+		if ext := filepath.Ext(f.path); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range strings.Split(string(data), "\n---") {
+			var parsed interface{}
+			if err := yaml.Unmarshal([]byte(doc), &parsed); err != nil {
+				// templated or otherwise invalid yaml still works as an example, it just has no suggestions
+				continue
+			}
+			walk(parsed, "", func(key, literal string) {
+				if seen[literal] {
+					return
+				}
+				seen[literal] = true
+				candidates = append(candidates, Candidate{Literal: literal, Key: key})
+			})
+		}
+	}
+	return candidates, nil
+}
+
+// VariableName suggests a variable name for a candidate that isn't in use yet.
+func VariableName(c Candidate, used map[string]bool) string {
+	name := strings.Trim(nonIdentifier.ReplaceAllString(c.Key, "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "value"
+	}
+
+	suggestion := name
+	for i := 2; used[suggestion]; i++ {
+		suggestion = fmt.Sprintf("%s_%d", name, i)
+	}
+	return suggestion
+}
+
+func walk(value interface{}, key string, fn func(key, literal string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !skippedKeys[k] {
+				walk(v[k], k, fn)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			walk(item, key, fn)
+		}
+	case string:
+		// single characters are too likely to occur as parts of other values, and values already holding liquid
+		// syntax are escaped by Templatize rather than replaced
+		if len(v) > 1 && !strings.Contains(v, "\n") && !strings.Contains(v, "{{") && !strings.Contains(v, "{%") {
+			fn(key, v)
+		}
+	case float64, bool:
+		fn(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,73 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// contracts edits a PrContracts file as a plain document, so fields scaffold doesn't know about survive
+type contracts struct {
+	doc map[string]interface{}
+}
+
+func readContracts(path string) (*contracts, error) {
+	if path == "" {
+		return nil, fmt.Errorf("a contracts file is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &contracts{doc: map[string]interface{}{
+			"apiVersion": "platform.plural.sh/v1alpha1",
+			"kind":       "PrContracts",
+			"metadata":   map[string]interface{}{"name": "pr-automation-contracts"},
+			"spec":       map[string]interface{}{"automations": []interface{}{}},
+		}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if kind, _ := doc["kind"].(string); kind != "PrContracts" {
+		return nil, fmt.Errorf("%s is not a PrContracts file", path)
+	}
+	if _, ok := doc["spec"].(map[string]interface{}); !ok {
+		doc["spec"] = map[string]interface{}{}
+	}
+	return &contracts{doc: doc}, nil
+}
+
+func (c *contracts) spec() map[string]interface{} {
+	return c.doc["spec"].(map[string]interface{})
+}
+
+// workdir is the absolute directory plural pr contracts runs the automations in, it is relative to the
+// directory the contracts are run from, which is assumed to be the current one
+func (c *contracts) workdir() (string, error) {
+	workdir, _ := c.spec()["workdir"].(string)
+	if workdir == "" {
+		workdir = "."
+	}
+	return filepath.Abs(workdir)
+}
+
+// add appends an automation, replacing an existing entry for the same file
+func (c *contracts) add(file, context string) {
+	entry := map[string]interface{}{"file": file, "context": context}
+
+	automations, _ := c.spec()["automations"].([]interface{})
+	for i, existing := range automations {
+		if m, ok := existing.(map[string]interface{}); ok && m["file"] == file {
+			automations[i] = entry
+			return
+		}
+	}
+	c.spec()["automations"] = append(automations, entry)
+}
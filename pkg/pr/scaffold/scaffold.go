@@ -0,0 +1,347 @@
+// Package scaffold generates a PrAutomation, its templates, a sample context and a contract test
+// from a set of example files.
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	TypeString = "STRING"
+	TypeInt    = "INT"
+	TypeBool   = "BOOL"
+
+	automationFile = "prautomation.yaml"
+	contextFile    = "context.yaml"
+	templatesDir   = "templates"
+)
+
+var (
+	nameRegex     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	variableRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Variable turns every occurrence of Literal in the example files into a configuration item.
+type Variable struct {
+	Name    string
+	Literal string
+	Type    string
+}
+
+type Options struct {
+	// Name of the generated PrAutomation.
+	Name string
+	// Sources are the example files or directories, their paths become the template destinations.
+	Sources []string
+	// Dir receives the PrAutomation, its templates and the sample context.
+	Dir string
+	// Contracts is the contracts file the automation is added to, it's created if missing.
+	Contracts string
+	Variables []Variable
+}
+
+// Result lists the files written by Scaffold.
+type Result struct {
+	Automation string
+	Context    string
+	Templates  []string
+	Contracts  string
+}
+
+type file struct {
+	path string
+	// rel is the path relative to the source it was found in
+	rel string
+}
+
+// Scaffold writes the PrAutomation and its templates. All paths in the generated files are relative to the
+// contracts workdir, so running the contracts with the sample context reproduces the example files unchanged.
+func Scaffold(opts Options) (*Result, error) {
+	if !nameRegex.MatchString(opts.Name) {
+		return nil, fmt.Errorf("invalid name %q, it must be a lowercase RFC 1123 label", opts.Name)
+	}
+	if err := validateVariables(opts.Variables); err != nil {
+		return nil, err
+	}
+
+	files, err := sourceFiles(opts.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := readContracts(opts.Contracts)
+	if err != nil {
+		return nil, err
+	}
+	base, err := contracts.workdir()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Automation: filepath.Join(opts.Dir, automationFile),
+		Context:    filepath.Join(opts.Dir, contextFile),
+		Templates:  make([]string, 0, len(files)),
+		Contracts:  opts.Contracts,
+	}
+
+	templates := make([]map[string]interface{}, 0, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return nil, err
+		}
+
+		dest := filepath.Join(opts.Dir, templatesDir, f.rel)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dest, []byte(Templatize(string(data), opts.Variables)), 0644); err != nil {
+			return nil, err
+		}
+		result.Templates = append(result.Templates, dest)
+
+		source, err := relativeTo(base, dest)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := relativeTo(base, f.path)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, map[string]interface{}{
+			"source":      source,
+			"destination": Templatize(destination, opts.Variables),
+			"external":    false,
+		})
+	}
+
+	if err := writeYaml(result.Automation, automation(opts, templates)); err != nil {
+		return nil, err
+	}
+	if err := writeYaml(result.Context, sampleContext(opts.Variables)); err != nil {
+		return nil, err
+	}
+
+	automationPath, err := relativeTo(base, result.Automation)
+	if err != nil {
+		return nil, err
+	}
+	contextPath, err := relativeTo(base, result.Context)
+	if err != nil {
+		return nil, err
+	}
+	contracts.add(automationPath, contextPath)
+	if err := writeYaml(opts.Contracts, contracts.doc); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Templatize escapes liquid syntax already in content and replaces every whole word occurrence of the
+// variable literals with a reference to the variable. Content is scanned once, so nothing is replaced inside a
+// reference or an escape, and where literals overlap the longest one wins.
+func Templatize(content string, variables []Variable) string {
+	sorted := make([]Variable, 0, len(variables))
+	for _, v := range variables {
+		if v.Literal != "" {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Literal) > len(sorted[j].Literal)
+	})
+
+	var sb strings.Builder
+	for i := 0; i < len(content); {
+		if strings.HasPrefix(content[i:], "{{") || strings.HasPrefix(content[i:], "{%") {
+			fmt.Fprintf(&sb, `{{ "%s" }}`, content[i:i+2])
+			i += 2
+			continue
+		}
+		if v, ok := wordAt(content, i, sorted); ok {
+			sb.WriteString("{{ context." + v.Name + " }}")
+			i += len(v.Literal)
+			continue
+		}
+		sb.WriteByte(content[i])
+		i++
+	}
+	return sb.String()
+}
+
+// InferType guesses the configuration type of a literal.
+func InferType(literal string) string {
+	if _, err := strconv.Atoi(literal); err == nil {
+		return TypeInt
+	}
+	if literal == "true" || literal == "false" {
+		return TypeBool
+	}
+	return TypeString
+}
+
+// wordAt returns the first variable whose literal occurs at i as a whole word
+func wordAt(content string, i int, variables []Variable) (Variable, bool) {
+	if isWordByte(content, i-1) {
+		return Variable{}, false
+	}
+	for _, v := range variables {
+		end := i + len(v.Literal)
+		if strings.HasPrefix(content[i:], v.Literal) && !isWordByte(content, end) {
+			return v, true
+		}
+	}
+	return Variable{}, false
+}
+
+func isWordByte(content string, i int) bool {
+	if i < 0 || i >= len(content) {
+		return false
+	}
+	c := content[i]
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func validateVariables(variables []Variable) error {
+	names := map[string]bool{}
+	for _, v := range variables {
+		if !variableRegex.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name %q, it must be a valid identifier", v.Name)
+		}
+		if names[v.Name] {
+			return fmt.Errorf("variable %s is defined more than once", v.Name)
+		}
+		names[v.Name] = true
+		if v.Literal == "" {
+			return fmt.Errorf("variable %s has no literal to replace", v.Name)
+		}
+
+		switch v.Type {
+		case TypeInt:
+			if _, err := strconv.Atoi(v.Literal); err != nil {
+				return fmt.Errorf("variable %s is an INT but %q is not an integer", v.Name, v.Literal)
+			}
+		case TypeBool:
+			if v.Literal != "true" && v.Literal != "false" {
+				return fmt.Errorf("variable %s is a BOOL but %q is not a boolean", v.Name, v.Literal)
+			}
+		case TypeString:
+		default:
+			return fmt.Errorf("variable %s has unsupported type %q", v.Name, v.Type)
+		}
+	}
+	return nil
+}
+
+func sourceFiles(sources []string) ([]file, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one example file is required")
+	}
+
+	files := make([]file, 0)
+	for _, source := range sources {
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, file{path: source, rel: filepath.Base(source)})
+			continue
+		}
+
+		if err := filepath.WalkDir(source, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(source, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, file{path: path, rel: filepath.Join(filepath.Base(filepath.Clean(source)), rel)})
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func automation(opts Options, templates []map[string]interface{}) map[string]interface{} {
+	configuration := make([]map[string]interface{}, 0, len(opts.Variables))
+	for _, v := range opts.Variables {
+		configuration = append(configuration, map[string]interface{}{
+			"name":          v.Name,
+			"type":          v.Type,
+			"documentation": fmt.Sprintf("replaces %q in the example files", v.Literal),
+		})
+	}
+
+	spec := map[string]interface{}{
+		"name":          opts.Name,
+		"documentation": fmt.Sprintf("Generated by plural pr scaffold from %s", strings.Join(opts.Sources, ", ")),
+		"title":         fmt.Sprintf("Applying %s", opts.Name),
+		"message":       fmt.Sprintf("Applying %s", opts.Name),
+		// the console rejects automations without an scm connection, this one is meant to be edited
+		"scmConnectionRef": map[string]interface{}{"name": "github"},
+		"creates":          map[string]interface{}{"templates": templates},
+	}
+	if len(configuration) > 0 {
+		spec["configuration"] = configuration
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "deployments.plural.sh/v1alpha1",
+		"kind":       "PrAutomation",
+		"metadata":   map[string]interface{}{"name": opts.Name},
+		"spec":       spec,
+	}
+}
+
+// sampleContext maps every variable onto its literal, which reproduces the example files
+func sampleContext(variables []Variable) map[string]interface{} {
+	ctx := map[string]interface{}{}
+	for _, v := range variables {
+		switch v.Type {
+		case TypeInt:
+			ctx[v.Name], _ = strconv.Atoi(v.Literal)
+		case TypeBool:
+			ctx[v.Name] = v.Literal == "true"
+		default:
+			ctx[v.Name] = v.Literal
+		}
+	}
+	return ctx
+}
+
+func relativeTo(base, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func writeYaml(path string, doc interface{}) error {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
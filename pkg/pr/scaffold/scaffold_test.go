@@ -0,0 +1,134 @@
+package scaffold_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/pluralsh/plural-cli/pkg/pr/scaffold"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: billing
+  namespace: billing-prod
+spec:
+  replicas: 3
+  template:
+    metadata:
+      annotations:
+        note: "{{ not a variable }}"
+`
+
+func TestTemplatize(t *testing.T) {
+	variables := []scaffold.Variable{
+		{Name: "name", Literal: "billing", Type: scaffold.TypeString},
+		{Name: "namespace", Literal: "billing-prod", Type: scaffold.TypeString},
+		{Name: "replicas", Literal: "3", Type: scaffold.TypeInt},
+	}
+
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ context.name }}
+  namespace: {{ context.namespace }}
+spec:
+  replicas: {{ context.replicas }}
+  template:
+    metadata:
+      annotations:
+        note: "{{ "{{" }} not a variable }}"
+`, scaffold.Templatize(deployment, variables))
+	assert.Equal(t, "billings and xbilling stay", scaffold.Templatize("billings and xbilling stay", variables))
+
+	// a literal that also occurs in the reference of another variable is only replaced where it was in the content
+	overlapping := []scaffold.Variable{
+		{Name: "namespace", Literal: "billing-prod", Type: scaffold.TypeString},
+		{Name: "key", Literal: "namespace", Type: scaffold.TypeString},
+	}
+	assert.Equal(t, "{{ context.key }}: {{ context.namespace }}", scaffold.Templatize("namespace: billing-prod", overlapping))
+}
+
+func TestCandidates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(deployment), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("billing"), 0644))
+
+	candidates, err := scaffold.Candidates([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []scaffold.Candidate{
+		{Literal: "billing", Key: "name"},
+		{Literal: "billing-prod", Key: "namespace"},
+		{Literal: "3", Key: "replicas"},
+	}, candidates)
+
+	assert.Equal(t, "name_2", scaffold.VariableName(candidates[0], map[string]bool{"name": true}))
+}
+
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join("services", "billing"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("services", "billing", "deployment.yaml"), []byte(deployment), 0644))
+	require.NoError(t, os.WriteFile("contracts.yaml", []byte(`apiVersion: platform.plural.sh/v1alpha1
+kind: PrContracts
+metadata:
+  name: existing
+spec:
+  automations:
+    - file: other.yaml
+`), 0644))
+
+	result, err := scaffold.Scaffold(scaffold.Options{
+		Name:      "billing",
+		Sources:   []string{filepath.Join("services", "billing")},
+		Dir:       filepath.Join("prautomations", "billing"),
+		Contracts: "contracts.yaml",
+		Variables: []scaffold.Variable{
+			{Name: "name", Literal: "billing", Type: scaffold.TypeString},
+			{Name: "replicas", Literal: "3", Type: scaffold.TypeInt},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("prautomations", "billing", "templates", "billing", "deployment.yaml")}, result.Templates)
+
+	automation := map[string]interface{}{}
+	readYaml(t, result.Automation, &automation)
+	spec := automation["spec"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"source":      "prautomations/billing/templates/billing/deployment.yaml",
+		"destination": "services/{{ context.name }}/deployment.yaml",
+		"external":    false,
+	}}, spec["creates"].(map[string]interface{})["templates"])
+	assert.Len(t, spec["configuration"], 2)
+
+	ctx := map[string]interface{}{}
+	readYaml(t, result.Context, &ctx)
+	assert.Equal(t, map[string]interface{}{"name": "billing", "replicas": float64(3)}, ctx)
+
+	contracts := map[string]interface{}{}
+	readYaml(t, "contracts.yaml", &contracts)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"file": "other.yaml"},
+		map[string]interface{}{"file": "prautomations/billing/prautomation.yaml", "context": "prautomations/billing/context.yaml"},
+	}, contracts["spec"].(map[string]interface{})["automations"])
+
+	_, err = scaffold.Scaffold(scaffold.Options{Name: "Billing", Sources: []string{"services"}, Contracts: "contracts.yaml"})
+	assert.EqualError(t, err, `invalid name "Billing", it must be a lowercase RFC 1123 label`)
+}
+
+func readYaml(t *testing.T, path string, out interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, out))
+}
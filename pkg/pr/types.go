@@ -14,6 +14,18 @@ type PrTemplate struct {
 	Metadata   map[string]interface{} `json:"metadata"`
 	Context    map[string]interface{} `json:"context"`
 	Spec       PrTemplateSpec         `json:"spec"`
+
+	// Warnings lists the spec fields of a PrAutomation that BuildCRD could not map
+	Warnings []string `json:"-"`
+	cleanups []func()
+}
+
+// Cleanup removes the repositories fetched while building the template.
+func (t *PrTemplate) Cleanup() {
+	for _, cleanup := range t.cleanups {
+		cleanup()
+	}
+	t.cleanups = nil
 }
 
 type PrTemplateSpec struct {
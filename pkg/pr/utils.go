@@ -4,8 +4,9 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/hashicorp/go-bexpr"
 	"github.com/pluralsh/console/go/polly/template"
+
+	"github.com/pluralsh/plural-cli/pkg/condition"
 )
 
 func templateReplacement(data []byte, ctx map[string]interface{}) ([]byte, error) {
@@ -57,15 +58,11 @@ func removeMatches(glob string) error {
 	return nil
 }
 
-func evaluateCondition(condition string, context map[string]interface{}) (bool, error) {
-	if len(condition) == 0 {
-		return true, nil
-	}
-
-	eval, err := bexpr.CreateEvaluator(condition, bexpr.WithUnknownValue(""))
+func evaluateCondition(cond string, context map[string]interface{}) (bool, error) {
+	expr, err := condition.Parse(cond)
 	if err != nil {
 		return false, err
 	}
 
-	return eval.Evaluate(map[string]interface{}{"context": context})
+	return expr.Evaluate(context)
 }
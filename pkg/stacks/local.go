@@ -0,0 +1,139 @@
+package stacks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pluralsh/plural-cli/pkg/utils/git"
+)
+
+// LocalVarsFileName holds the stack variables, terraform loads *.auto.tfvars.json files automatically.
+const LocalVarsFileName = "plural.auto.tfvars.json"
+
+// LocalStack is the configuration needed to reproduce a stack run on a workstation.
+type LocalStack struct {
+	// RepositoryURL is the git repository the stack is sourced from.
+	RepositoryURL string
+	// Ref is the branch, tag or commit of the stack.
+	Ref string
+	// Folder is the path of the stack within the repository.
+	Folder string
+	// Variables are written to LocalVarsFileName in the stack folder.
+	Variables map[string]any
+	// Environment is passed to terraform.
+	Environment map[string]string
+	// Files maps paths relative to the stack folder onto their content.
+	Files map[string]string
+	// StateAddress is the console's http state backend address for the stack.
+	StateAddress string
+}
+
+// LocalPlanOptions configures a local plan.
+type LocalPlanOptions struct {
+	// Dir is the checkout directory, it must not exist or be empty.
+	Dir string
+	// Actor and DeployToken authenticate against the state backend.
+	Actor       string
+	DeployToken string
+}
+
+// Prepare checks the stack folder out into dir and writes the backend override, variables and files,
+// returning the directory terraform should run in.
+func (s *LocalStack) Prepare(opts LocalPlanOptions) (string, error) {
+	if s.RepositoryURL == "" || s.Ref == "" {
+		return "", fmt.Errorf("stack has no git repository configured")
+	}
+
+	if err := git.ShallowClone(s.RepositoryURL, s.Ref, opts.Dir); err != nil {
+		return "", err
+	}
+
+	workdir, err := localPath(opts.Dir, s.Folder)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(workdir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("folder %q does not exist at %s", s.Folder, s.Ref)
+	}
+
+	// lock addresses are left out so a local plan can never hold the lock of a console run
+	if _, err := GenerateOverrideTemplate(&OverrideTemplateInput{
+		Address:     s.StateAddress,
+		Actor:       opts.Actor,
+		DeployToken: opts.DeployToken,
+	}, workdir); err != nil {
+		return "", err
+	}
+
+	if len(s.Variables) > 0 {
+		data, err := json.MarshalIndent(s.Variables, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(workdir, LocalVarsFileName), data, 0600); err != nil {
+			return "", err
+		}
+	}
+
+	for path, content := range s.Files {
+		dest, err := localPath(workdir, path)
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(dest, []byte(content), 0600); err != nil {
+			return "", err
+		}
+	}
+
+	return workdir, nil
+}
+
+// Plan runs terraform init and plan in workdir with state locking disabled, appending args to terraform plan.
+func (s *LocalStack) Plan(ctx context.Context, workdir string, args []string) error {
+	if err := s.terraform(ctx, workdir, "init", "-input=false"); err != nil {
+		return err
+	}
+
+	return s.terraform(ctx, workdir, append([]string{"plan", "-input=false", "-lock=false"}, args...)...)
+}
+
+func (s *LocalStack) terraform(ctx context.Context, workdir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd.Dir = workdir
+	cmd.Env = append(os.Environ(), s.env()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("terraform %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+func (s *LocalStack) env() []string {
+	env := make([]string, 0, len(s.Environment))
+	for name, value := range s.Environment {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// localPath joins path onto root, rejecting paths that escape it
+func localPath(root, path string) (string, error) {
+	joined := filepath.Join(root, filepath.FromSlash(path))
+	rel, err := filepath.Rel(root, joined)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the stack directory", path)
+	}
+	return joined, nil
+}
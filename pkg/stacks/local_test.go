@@ -0,0 +1,77 @@
+package stacks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStackPrepare(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	origin := t.TempDir()
+	writeTestFile(t, filepath.Join(origin, "terraform", "main.tf"), `variable "region" {}`)
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = origin
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s", args, out)
+		}
+	}
+
+	stack := &LocalStack{
+		RepositoryURL: origin,
+		Ref:           "main",
+		Folder:        "terraform",
+		Variables:     map[string]any{"region": "us-east-1"},
+		Files:         map[string]string{"config/settings.json": "{}"},
+		StateAddress:  "https://console.example.com/ext/v1/states/terraform/stack-1",
+	}
+	workdir, err := stack.Prepare(LocalPlanOptions{
+		Dir:         filepath.Join(t.TempDir(), "checkout"),
+		Actor:       "user@example.com",
+		DeployToken: "token",
+	})
+	if err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+
+	override, err := os.ReadFile(filepath.Join(workdir, "_override.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(override), stack.StateAddress) || strings.Contains(string(override), "lock_address") {
+		t.Fatalf("expected a lock free backend override, got:\n%s", override)
+	}
+	vars, err := os.ReadFile(filepath.Join(workdir, LocalVarsFileName))
+	if err != nil || !strings.Contains(string(vars), `"region": "us-east-1"`) {
+		t.Fatalf("expected variables to be written, got %q %v", vars, err)
+	}
+	if _, err := os.Stat(filepath.Join(workdir, "config", "settings.json")); err != nil {
+		t.Fatalf("expected stack file to be written: %v", err)
+	}
+}
+
+func TestLocalStackPrepareRejectsEscapingFiles(t *testing.T) {
+	if _, err := localPath("/tmp/stack", "../../etc/passwd"); err == nil {
+		t.Fatalf("expected escaping path to be rejected")
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,182 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+
+	"github.com/Masterminds/sprig/v3"
+	pollytemplate "github.com/pluralsh/console/go/polly/template"
+)
+
+const (
+	EngineTpl    = "tpl"
+	EngineLiquid = "liquid"
+
+	// ModeAgent renders with exactly the functions the deployment operator has, so local output equals cluster output.
+	ModeAgent = "agent"
+	// ModeCli adds the cli helpers used by `plural up` templates to tpl files.
+	ModeCli = "cli"
+
+	SourceSprig  = "sprig"
+	SourcePolly  = "polly"
+	SourcePlural = "plural"
+
+	pollyModule = "github.com/pluralsh/console/go/polly"
+)
+
+type Function struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Engine      string   `json:"engine"`
+	Source      string   `json:"source"`
+	Description string   `json:"description,omitempty"`
+	Parameters  []string `json:"parameters,omitempty"`
+	Example     string   `json:"example,omitempty"`
+}
+
+// Catalog lists the functions available to templates. Version is the version of the renderer shared with the
+// deployment operator, both produce the same output when they run the same version.
+type Catalog struct {
+	Version   string     `json:"version"`
+	Mode      string     `json:"mode"`
+	Functions []Function `json:"functions"`
+}
+
+var pluralFunctionDocs = map[string]string{
+	"genAESKey":       "Generates a random base64 encoded AES key.",
+	"repoRoot":        "Returns the root folder of the current git repository.",
+	"repoName":        "Returns the name of the current git repository.",
+	"repoUrl":         "Returns the remote url of the current git repository.",
+	"branchName":      "Returns the checked out git branch.",
+	"dumpConfig":      "Returns the plural config file as yaml.",
+	"dumpAesKey":      "Returns the AES key used to encrypt the repository as yaml.",
+	"readLine":        "Prompts for a line of input.",
+	"readPassword":    "Prompts for a password without echoing it.",
+	"readLineDefault": "Prompts for a line of input, returning the default if it's empty.",
+	"readFile":        "Returns the content of a file, or an empty string if it can't be read.",
+	"homeDir":         "Joins the paths onto the home directory of the user.",
+	"knownHosts":      "Returns the content of ~/.ssh/known_hosts.",
+	"dedupe":          "Returns the value at the path of an object if set, otherwise the given value.",
+	"dedupeObj":       "Returns the object at the path of an object if set, otherwise the given object.",
+	"secret":          "Reads a kubernetes secret, returning its decoded data.",
+	"probe":           "Returns the value at a dotted path of an object.",
+	"importValue":     "Reads a value from a file, such as a terraform output.",
+	"namespace":       "Returns the namespace of an application in the workspace.",
+	"toYaml":          "Marshals a value to yaml.",
+	"fileExists":      "Reports whether a file exists.",
+	"pathJoin":        "Joins path elements.",
+	"eabCredential":   "Fetches the ACME EAB credential for a cluster and provider.",
+}
+
+// FunctionCatalog lists the functions of both template engines in the given mode.
+func FunctionCatalog(mode string) (*Catalog, error) {
+	if err := validateMode(mode); err != nil {
+		return nil, err
+	}
+
+	filters := pollytemplate.RegisteredFilters()
+	functions := make([]Function, 0, len(filters))
+	for name, filter := range filters {
+		functions = append(functions, Function{
+			Name:        name,
+			Aliases:     filter.Aliases,
+			Engine:      EngineLiquid,
+			Source:      SourcePolly,
+			Description: filter.Documentation.Description,
+			Parameters:  filter.Documentation.Parameters,
+			Example:     filter.Documentation.Example,
+		})
+	}
+
+	for name := range tplFuncNames(mode) {
+		fn := Function{Name: name, Engine: EngineTpl, Source: SourceSprig}
+		if description, ok := pluralFunctionDocs[name]; ok {
+			fn.Source = SourcePlural
+			fn.Description = description
+		} else if name == "include" {
+			fn.Source = SourcePolly
+			fn.Description = "Renders a template defined in the same file with the given data."
+		} else if filter, ok := filters[name]; ok {
+			fn.Description = filter.Documentation.Description
+			fn.Parameters = filter.Documentation.Parameters
+			fn.Example = filter.Documentation.Example
+		}
+		functions = append(functions, fn)
+	}
+
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Engine != functions[j].Engine {
+			return functions[i].Engine < functions[j].Engine
+		}
+		return functions[i].Name < functions[j].Name
+	})
+	return &Catalog{Version: RendererVersion(), Mode: mode, Functions: functions}, nil
+}
+
+// RendererVersion returns the version of the template renderer compiled into the cli.
+func RendererVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == pollyModule {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// Render renders a .tpl or .liquid file with the given bindings. Liquid files always render like the deployment
+// operator, tpl files only get the cli helpers in cli mode.
+func Render(path string, content []byte, bindings map[string]interface{}, mode string) ([]byte, error) {
+	if err := validateMode(mode); err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".liquid":
+		return pollytemplate.RenderLiquid(content, bindings)
+	case ".tpl", ".gotmpl":
+		if mode == ModeAgent {
+			return pollytemplate.RenderTpl(content, bindings)
+		}
+		tpl, err := MakeTemplate(string(content))
+		if err != nil {
+			return nil, err
+		}
+		var buffer bytes.Buffer
+		err = tpl.Execute(&buffer, bindings)
+		return buffer.Bytes(), err
+	}
+	return nil, fmt.Errorf("%s is not a .liquid or .tpl file", path)
+}
+
+func tplFuncNames(mode string) map[string]struct{} {
+	names := map[string]struct{}{}
+	if mode == ModeCli {
+		for name := range GetFuncMap() {
+			names[name] = struct{}{}
+		}
+		return names
+	}
+
+	for name := range sprig.TxtFuncMap() {
+		names[name] = struct{}{}
+	}
+	names["include"] = struct{}{}
+	return names
+}
+
+func validateMode(mode string) error {
+	if mode != ModeAgent && mode != ModeCli {
+		return fmt.Errorf("unsupported mode %q, must be one of %s or %s", mode, ModeAgent, ModeCli)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package template_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/template"
+)
+
+func TestFunctionCatalog(t *testing.T) {
+	find := func(catalog *template.Catalog, engine, name string) *template.Function {
+		for _, fn := range catalog.Functions {
+			if fn.Engine == engine && fn.Name == name {
+				return &fn
+			}
+		}
+		return nil
+	}
+
+	agent, err := template.FunctionCatalog(template.ModeAgent)
+	require.NoError(t, err)
+	assert.NotNil(t, find(agent, template.EngineLiquid, "indent"))
+	assert.NotNil(t, find(agent, template.EngineTpl, "include"))
+	assert.Nil(t, find(agent, template.EngineTpl, "readLine"))
+	if fn := find(agent, template.EngineTpl, "add"); assert.NotNil(t, fn) {
+		assert.Equal(t, template.SourceSprig, fn.Source)
+		assert.NotEmpty(t, fn.Description)
+	}
+
+	cli, err := template.FunctionCatalog(template.ModeCli)
+	require.NoError(t, err)
+	if fn := find(cli, template.EngineTpl, "readLine"); assert.NotNil(t, fn) {
+		assert.Equal(t, template.SourcePlural, fn.Source)
+	}
+
+	_, err = template.FunctionCatalog("cluster")
+	assert.EqualError(t, err, `unsupported mode "cluster", must be one of agent or cli`)
+}
+
+func TestRender(t *testing.T) {
+	bindings := map[string]interface{}{"name": "app", "replicas": 2}
+
+	out, err := template.Render("deployment.yaml.liquid", []byte(`{{ name | upper }}: {{ replicas | add: 1 }}`), bindings, template.ModeAgent)
+	require.NoError(t, err)
+	assert.Equal(t, "APP: 3", string(out))
+
+	out, err = template.Render("deployment.tpl", []byte(`{{ define "n" }}{{ .name }}{{ end }}{{ include "n" . | upper }}`), bindings, template.ModeAgent)
+	require.NoError(t, err)
+	assert.Equal(t, "APP", string(out))
+
+	out, err = template.Render("deployment.tpl", []byte(`{{ pathJoin "a" .name }}`), bindings, template.ModeCli)
+	require.NoError(t, err)
+	assert.Equal(t, "a/app", string(out))
+
+	_, err = template.Render("deployment.tpl", []byte(`{{ pathJoin "a" .name }}`), bindings, template.ModeAgent)
+	assert.ErrorContains(t, err, `function "pathJoin" not defined`)
+
+	_, err = template.Render("deployment.yaml", nil, bindings, template.ModeAgent)
+	assert.EqualError(t, err, "deployment.yaml is not a .liquid or .tpl file")
+}
@@ -15,6 +15,34 @@ type ConsoleClient struct {
 	mock.Mock
 }
 
+// AgentRunSessionUploadURL provides a mock function with given fields: id
+func (_m *ConsoleClient) AgentRunSessionUploadURL(id string) (string, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AgentRunSessionUploadURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AgentUrl provides a mock function with given fields: id
 func (_m *ConsoleClient) AgentUrl(id string) (string, error) {
 	ret := _m.Called(id)
@@ -43,6 +71,42 @@ func (_m *ConsoleClient) AgentUrl(id string) (string, error) {
 	return r0, r1
 }
 
+// ApproveStackRun provides a mock function with given fields: id
+func (_m *ConsoleClient) ApproveStackRun(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveStackRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelAgentRun provides a mock function with given fields: id
+func (_m *ConsoleClient) CancelAgentRun(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelAgentRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CloneService provides a mock function with given fields: clusterId, serviceId, serviceName, clusterName, attributes
 func (_m *ConsoleClient) CloneService(clusterId string, serviceId *string, serviceName *string, clusterName *string, attributes client.ServiceCloneAttributes) (*client.ServiceDeploymentFragment, error) {
 	ret := _m.Called(clusterId, serviceId, serviceName, clusterName, attributes)
@@ -73,6 +137,34 @@ func (_m *ConsoleClient) CloneService(clusterId string, serviceId *string, servi
 	return r0, r1
 }
 
+// CreateAgentRun provides a mock function with given fields: runtimeID, attributes
+func (_m *ConsoleClient) CreateAgentRun(runtimeID string, attributes client.AgentRunAttributes) (string, error) {
+	ret := _m.Called(runtimeID, attributes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAgentRun")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, client.AgentRunAttributes) (string, error)); ok {
+		return rf(runtimeID, attributes)
+	}
+	if rf, ok := ret.Get(0).(func(string, client.AgentRunAttributes) string); ok {
+		r0 = rf(runtimeID, attributes)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, client.AgentRunAttributes) error); ok {
+		r1 = rf(runtimeID, attributes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateBootstrapToken provides a mock function with given fields: attributes
 func (_m *ConsoleClient) CreateBootstrapToken(attributes client.BootstrapTokenAttributes) (string, error) {
 	ret := _m.Called(attributes)
@@ -371,6 +463,34 @@ func (_m *ConsoleClient) CreateRepository(url string, privateKey *string, passph
 	return r0, r1
 }
 
+// CreateWorkbenchMessage provides a mock function with given fields: jobID, prompt
+func (_m *ConsoleClient) CreateWorkbenchMessage(jobID string, prompt string) (string, error) {
+	ret := _m.Called(jobID, prompt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWorkbenchMessage")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(jobID, prompt)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(jobID, prompt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(jobID, prompt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateWorkbenchPRFollowup provides a mock function with given fields: url, prompt
 func (_m *ConsoleClient) CreateWorkbenchPRFollowup(url string, prompt string) (string, error) {
 	ret := _m.Called(url, prompt)
@@ -477,6 +597,24 @@ func (_m *ConsoleClient) DeleteProviderCredentials(id string) (*client.DeletePro
 	return r0, r1
 }
 
+// DeleteQueuedPrompt provides a mock function with given fields: id
+func (_m *ConsoleClient) DeleteQueuedPrompt(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteQueuedPrompt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DetachCluster provides a mock function with given fields: id
 func (_m *ConsoleClient) DetachCluster(id string) error {
 	ret := _m.Called(id)
@@ -573,6 +711,66 @@ func (_m *ConsoleClient) GetAgentRun(id string) (*client.AgentRunMinimalFragment
 	return r0, r1
 }
 
+// GetAgentRunMessages provides a mock function with given fields: id
+func (_m *ConsoleClient) GetAgentRunMessages(id string) ([]*client.AgentMessageFragment, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgentRunMessages")
+	}
+
+	var r0 []*client.AgentMessageFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*client.AgentMessageFragment, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*client.AgentMessageFragment); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.AgentMessageFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAgentRuntime provides a mock function with given fields: runtime
+func (_m *ConsoleClient) GetAgentRuntime(runtime client.AgentRuntimeType) (*client.AgentRuntimeFragment, error) {
+	ret := _m.Called(runtime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgentRuntime")
+	}
+
+	var r0 *client.AgentRuntimeFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(client.AgentRuntimeType) (*client.AgentRuntimeFragment, error)); ok {
+		return rf(runtime)
+	}
+	if rf, ok := ret.Get(0).(func(client.AgentRuntimeType) *client.AgentRuntimeFragment); ok {
+		r0 = rf(runtime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.AgentRuntimeFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(client.AgentRuntimeType) error); ok {
+		r1 = rf(runtime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCluster provides a mock function with given fields: clusterId, clusterName
 func (_m *ConsoleClient) GetCluster(clusterId *string, clusterName *string) (*client.ClusterFragment, error) {
 	ret := _m.Called(clusterId, clusterName)
@@ -811,6 +1009,36 @@ func (_m *ConsoleClient) GetProject(name string) (*client.ProjectFragment, error
 	return r0, r1
 }
 
+// GetPullRequest provides a mock function with given fields: id
+func (_m *ConsoleClient) GetPullRequest(id string) (*client.PullRequestFragment, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPullRequest")
+	}
+
+	var r0 *client.PullRequestFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*client.PullRequestFragment, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *client.PullRequestFragment); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.PullRequestFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRepository provides a mock function with given fields: id
 func (_m *ConsoleClient) GetRepository(id string) (*client.GetGitRepository, error) {
 	ret := _m.Called(id)
@@ -871,6 +1099,66 @@ func (_m *ConsoleClient) GetServiceContext(name string) (*client.ServiceContextF
 	return r0, r1
 }
 
+// GetStack provides a mock function with given fields: id
+func (_m *ConsoleClient) GetStack(id string) (*client.InfrastructureStackFragment, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStack")
+	}
+
+	var r0 *client.InfrastructureStackFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*client.InfrastructureStackFragment, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *client.InfrastructureStackFragment); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.InfrastructureStackFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStackRun provides a mock function with given fields: id
+func (_m *ConsoleClient) GetStackRun(id string) (*client.StackRunFragment, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStackRun")
+	}
+
+	var r0 *client.StackRunFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*client.StackRunFragment, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *client.StackRunFragment); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.StackRunFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: email
 func (_m *ConsoleClient) GetUser(email string) (*client.UserFragment, error) {
 	ret := _m.Called(email)
@@ -901,6 +1189,36 @@ func (_m *ConsoleClient) GetUser(email string) (*client.UserFragment, error) {
 	return r0, r1
 }
 
+// GetWorkbenchJob provides a mock function with given fields: id
+func (_m *ConsoleClient) GetWorkbenchJob(id string) (*client.WorkbenchJobTinyFragment, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkbenchJob")
+	}
+
+	var r0 *client.WorkbenchJobTinyFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*client.WorkbenchJobTinyFragment, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *client.WorkbenchJobTinyFragment); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.WorkbenchJobTinyFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsClusterRegistrationComplete provides a mock function with given fields: machineID
 func (_m *ConsoleClient) IsClusterRegistrationComplete(machineID string) (bool, *client.ClusterRegistrationFragment) {
 	ret := _m.Called(machineID)
@@ -991,6 +1309,36 @@ func (_m *ConsoleClient) ListAgentRuns(first int64) ([]*client.AgentRunMinimalFr
 	return r0, r1
 }
 
+// ListAgentRunsPage provides a mock function with given fields: after, first
+func (_m *ConsoleClient) ListAgentRunsPage(after *string, first int64) (*client.ListAgentRunsMinimal_AgentRuns, error) {
+	ret := _m.Called(after, first)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgentRunsPage")
+	}
+
+	var r0 *client.ListAgentRunsMinimal_AgentRuns
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*string, int64) (*client.ListAgentRunsMinimal_AgentRuns, error)); ok {
+		return rf(after, first)
+	}
+	if rf, ok := ret.Get(0).(func(*string, int64) *client.ListAgentRunsMinimal_AgentRuns); ok {
+		r0 = rf(after, first)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.ListAgentRunsMinimal_AgentRuns)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, int64) error); ok {
+		r1 = rf(after, first)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListClusterServices provides a mock function with given fields: clusterId, handle
 func (_m *ConsoleClient) ListClusterServices(clusterId *string, handle *string) ([]*client.ServiceDeploymentEdgeFragment, error) {
 	ret := _m.Called(clusterId, handle)
@@ -1111,6 +1459,45 @@ func (_m *ConsoleClient) ListProviders() (*client.ListProviders, error) {
 	return r0, r1
 }
 
+// ListPullRequests provides a mock function with given fields: clusterID, serviceID, after, first
+func (_m *ConsoleClient) ListPullRequests(clusterID *string, serviceID *string, after *string, first int64) ([]*client.PullRequestFragment, *client.PageInfoFragment, error) {
+	ret := _m.Called(clusterID, serviceID, after, first)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullRequests")
+	}
+
+	var r0 []*client.PullRequestFragment
+	var r1 *client.PageInfoFragment
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*string, *string, *string, int64) ([]*client.PullRequestFragment, *client.PageInfoFragment, error)); ok {
+		return rf(clusterID, serviceID, after, first)
+	}
+	if rf, ok := ret.Get(0).(func(*string, *string, *string, int64) []*client.PullRequestFragment); ok {
+		r0 = rf(clusterID, serviceID, after, first)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.PullRequestFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*string, *string, *string, int64) *client.PageInfoFragment); ok {
+		r1 = rf(clusterID, serviceID, after, first)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*client.PageInfoFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*string, *string, *string, int64) error); ok {
+		r2 = rf(clusterID, serviceID, after, first)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ListRepositories provides a mock function with no fields
 func (_m *ConsoleClient) ListRepositories() (*client.ListGitRepositories, error) {
 	ret := _m.Called()
@@ -1201,6 +1588,144 @@ func (_m *ConsoleClient) ListStacks() (*client.ListInfrastructureStacks, error)
 	return r0, r1
 }
 
+// ListWorkbenchJobActivities provides a mock function with given fields: id, after, first
+func (_m *ConsoleClient) ListWorkbenchJobActivities(id string, after *string, first int64) ([]*client.WorkbenchJobActivityFragment, *client.PageInfoFragment, error) {
+	ret := _m.Called(id, after, first)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorkbenchJobActivities")
+	}
+
+	var r0 []*client.WorkbenchJobActivityFragment
+	var r1 *client.PageInfoFragment
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, *string, int64) ([]*client.WorkbenchJobActivityFragment, *client.PageInfoFragment, error)); ok {
+		return rf(id, after, first)
+	}
+	if rf, ok := ret.Get(0).(func(string, *string, int64) []*client.WorkbenchJobActivityFragment); ok {
+		r0 = rf(id, after, first)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.WorkbenchJobActivityFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *string, int64) *client.PageInfoFragment); ok {
+		r1 = rf(id, after, first)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*client.PageInfoFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, *string, int64) error); ok {
+		r2 = rf(id, after, first)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListWorkbenchJobs provides a mock function with given fields: workbenchID, after, first
+func (_m *ConsoleClient) ListWorkbenchJobs(workbenchID string, after *string, first int64) ([]*client.WorkbenchJobTinyFragment, *client.PageInfoFragment, error) {
+	ret := _m.Called(workbenchID, after, first)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorkbenchJobs")
+	}
+
+	var r0 []*client.WorkbenchJobTinyFragment
+	var r1 *client.PageInfoFragment
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, *string, int64) ([]*client.WorkbenchJobTinyFragment, *client.PageInfoFragment, error)); ok {
+		return rf(workbenchID, after, first)
+	}
+	if rf, ok := ret.Get(0).(func(string, *string, int64) []*client.WorkbenchJobTinyFragment); ok {
+		r0 = rf(workbenchID, after, first)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.WorkbenchJobTinyFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *string, int64) *client.PageInfoFragment); ok {
+		r1 = rf(workbenchID, after, first)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*client.PageInfoFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, *string, int64) error); ok {
+		r2 = rf(workbenchID, after, first)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListWorkbenchQueuedPrompts provides a mock function with given fields: jobID
+func (_m *ConsoleClient) ListWorkbenchQueuedPrompts(jobID string) ([]*client.QueuedPromptFragment, error) {
+	ret := _m.Called(jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorkbenchQueuedPrompts")
+	}
+
+	var r0 []*client.QueuedPromptFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*client.QueuedPromptFragment, error)); ok {
+		return rf(jobID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*client.QueuedPromptFragment); ok {
+		r0 = rf(jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.QueuedPromptFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWorkbenches provides a mock function with given fields: first
+func (_m *ConsoleClient) ListWorkbenches(first int64) ([]*client.WorkbenchTinyFragment, error) {
+	ret := _m.Called(first)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorkbenches")
+	}
+
+	var r0 []*client.WorkbenchTinyFragment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]*client.WorkbenchTinyFragment, error)); ok {
+		return rf(first)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []*client.WorkbenchTinyFragment); ok {
+		r0 = rf(first)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.WorkbenchTinyFragment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(first)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // MyCluster provides a mock function with no fields
 func (_m *ConsoleClient) MyCluster() (*client.MyCluster, error) {
 	ret := _m.Called()
@@ -1231,6 +1756,34 @@ func (_m *ConsoleClient) MyCluster() (*client.MyCluster, error) {
 	return r0, r1
 }
 
+// RestartStackRun provides a mock function with given fields: id
+func (_m *ConsoleClient) RestartStackRun(id string) (string, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestartStackRun")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SavePipeline provides a mock function with given fields: name, attrs
 func (_m *ConsoleClient) SavePipeline(name string, attrs client.PipelineAttributes) (*client.PipelineFragmentMinimal, error) {
 	ret := _m.Called(name, attrs)
@@ -1309,6 +1862,34 @@ func (_m *ConsoleClient) Token() string {
 	return r0
 }
 
+// TriggerStackRun provides a mock function with given fields: stackID
+func (_m *ConsoleClient) TriggerStackRun(stackID string) (string, error) {
+	ret := _m.Called(stackID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TriggerStackRun")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(stackID)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(stackID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(stackID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateCluster provides a mock function with given fields: id, attr
 func (_m *ConsoleClient) UpdateCluster(id string, attr client.ClusterUpdateAttributes) (*client.UpdateCluster, error) {
 	ret := _m.Called(id, attr)
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ShallowClone checks out only ref of url into dir, which works for branches, tags and commit shas alike, unlike
+// git clone -b. dir must not exist or be empty.
+func ShallowClone(url, ref, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", url},
+		{"fetch", "--quiet", "--depth", "1", "origin", ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		if _, err := git(dir, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchFolder shallow clones ref of url into a temporary directory and returns the path of folder within it. The
+// returned cleanup removes the directory, it is only set when err is nil.
+func FetchFolder(url, ref, folder string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "plural-git-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+
+	if err := ShallowClone(url, ref, dir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(folder))
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		cleanup()
+		return "", nil, fmt.Errorf("folder %q is outside of the repository", folder)
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		cleanup()
+		return "", nil, fmt.Errorf("folder %q does not exist in %s at %s", folder, url, ref)
+	}
+	return path, cleanup, nil
+}
@@ -0,0 +1,55 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluralsh/plural-cli/pkg/utils/git"
+)
+
+func TestFetchFolder(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "--quiet", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	run("config", "commit.gpgsign", "false")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "charts", "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "charts", "app", "values.yaml"), []byte("replicas: 1\n"), 0644))
+	run("add", ".")
+	run("commit", "--quiet", "-m", "first")
+	sha := run("rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "charts", "app", "values.yaml"), []byte("replicas: 2\n"), 0644))
+	run("commit", "--quiet", "-am", "second")
+
+	for ref, expected := range map[string]string{"main": "replicas: 2\n", sha: "replicas: 1\n"} {
+		path, cleanup, err := git.FetchFolder(repo, ref, "charts/app")
+		require.NoError(t, err, ref)
+		content, err := os.ReadFile(filepath.Join(path, "values.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content), ref)
+		cleanup()
+		assert.NoDirExists(t, path)
+	}
+
+	_, _, err := git.FetchFolder(repo, "main", "missing")
+	assert.ErrorContains(t, err, `folder "missing" does not exist`)
+	_, _, err = git.FetchFolder(repo, "main", "../outside")
+	assert.ErrorContains(t, err, "outside of the repository")
+}
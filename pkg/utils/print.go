@@ -110,3 +110,21 @@ func NewJsonPrinter(i any) Printer {
 func NewYAMLPrinter(i any) Printer {
 	return &yamlPrinter{i: i}
 }
+
+// PrintOutput prints data in the format requested with an -o flag, one of json, yaml or jsonpath=<expression>. It
+// returns false without printing anything when no format was requested, so callers can fall back to a table.
+func PrintOutput(output string, data any) (bool, error) {
+	switch {
+	case output == "json":
+		NewJsonPrinter(data).PrettyPrint()
+	case output == "yaml":
+		NewYAMLPrinter(data).PrettyPrint()
+	case strings.HasPrefix(output, "jsonpath="):
+		return true, ParseJSONPath(output, data)
+	case output == "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported output format %q, must be one of json, yaml or jsonpath=", output)
+	}
+	return true, nil
+}
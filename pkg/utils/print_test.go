@@ -0,0 +1,23 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func TestPrintOutputRejectsUnknownFormat(t *testing.T) {
+	printed, err := utils.PrintOutput("xml", nil)
+
+	assert.False(t, printed)
+	assert.EqualError(t, err, `unsupported output format "xml", must be one of json, yaml or jsonpath=`)
+}
+
+func TestPrintOutputWithoutFormat(t *testing.T) {
+	printed, err := utils.PrintOutput("", map[string]string{"a": "b"})
+
+	assert.False(t, printed)
+	assert.NoError(t, err)
+}
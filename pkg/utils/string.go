@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 )
 
 func Pluralize(one, many string, count int) string {
@@ -14,3 +15,17 @@ func Pluralize(one, many string, count int) string {
 func ToString(val interface{}) string {
 	return fmt.Sprintf("%v", val)
 }
+
+// Shorten cuts s to at most length characters, marking the cut with an ellipsis.
+func Shorten(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:max(length-3, 0)]) + "..."
+}
+
+// Summarize collapses whitespace and newlines in s and shortens it, for showing free text in a table cell.
+func Summarize(s string, length int) string {
+	return Shorten(strings.Join(strings.Fields(s), " "), length)
+}
@@ -0,0 +1,20 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pluralsh/plural-cli/pkg/utils"
+)
+
+func TestShorten(t *testing.T) {
+	assert.Equal(t, "short", utils.Shorten("short", 10))
+	assert.Equal(t, "exactly 10", utils.Shorten("exactly 10", 10))
+	assert.Equal(t, "a longe...", utils.Shorten("a longer sentence", 10))
+	assert.Equal(t, "żółw ż...", utils.Shorten("żółw żółw żółw", 9))
+}
+
+func TestSummarize(t *testing.T) {
+	assert.Equal(t, "fix the build and...", utils.Summarize("fix the\n  build\tand push it", 20))
+}